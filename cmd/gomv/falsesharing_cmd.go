@@ -0,0 +1,158 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"github.com/Shomosyt1/go-memory-visualizer/internal/arch"
+	"github.com/Shomosyt1/go-memory-visualizer/internal/falsesharing"
+	"github.com/Shomosyt1/go-memory-visualizer/internal/layout"
+)
+
+// astFieldInfo is the source-level signal for one field: its declared type
+// text and any doc/line comments, used to decide whether it's "hot".
+type astFieldInfo struct {
+	typeName string
+	comment  string
+}
+
+// expandASTFields flattens astStruct's field list one entry per declared
+// name (e.g. "A, B atomic.Uint64" becomes two entries, both carrying that
+// type and comment), so the result lines up positionally with go/types'
+// *types.Struct field indices, which are similarly expanded per name.
+func expandASTFields(astStruct *ast.StructType) []astFieldInfo {
+	if astStruct == nil {
+		return nil
+	}
+	var out []astFieldInfo
+	for _, afield := range astStruct.Fields.List {
+		info := astFieldInfo{typeName: exprString(afield.Type)}
+		if afield.Doc != nil {
+			info.comment += afield.Doc.Text()
+		}
+		if afield.Comment != nil {
+			info.comment += afield.Comment.Text()
+		}
+		n := len(afield.Names)
+		if n == 0 {
+			n = 1 // embedded field: one name implied by its type
+		}
+		for i := 0; i < n; i++ {
+			out = append(out, info)
+		}
+	}
+	return out
+}
+
+// hotFields joins the computed offsets from layout with the source-level
+// signals (declared type text, doc/line comments) that mark a field hot.
+func hotFields(st *types.Struct, astStruct *ast.StructType, goarch string) []falsesharing.Field {
+	computed := layout.Compute(st, goarch)
+	astFields := expandASTFields(astStruct)
+	fields := make([]falsesharing.Field, len(computed.Fields))
+
+	for i, f := range computed.Fields {
+		typeName := ""
+		comment := ""
+		if i < len(astFields) {
+			typeName = astFields[i].typeName
+			comment = astFields[i].comment
+		}
+		reason, hot := falsesharing.HotReason(typeName, comment)
+		fields[i] = falsesharing.Field{
+			Name:   f.Name,
+			Offset: f.Offset,
+			Size:   f.Size,
+			Hot:    hot,
+			Reason: reason,
+		}
+	}
+	return fields
+}
+
+func exprString(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		return exprString(e.X) + "." + e.Sel.Name
+	case *ast.StarExpr:
+		return "*" + exprString(e.X)
+	case *ast.IndexExpr:
+		return exprString(e.X) + "[" + exprString(e.Index) + "]"
+	default:
+		return fmt.Sprintf("%T", expr)
+	}
+}
+
+func runDetect(args []string) error {
+	fs := flag.NewFlagSet("detect", flag.ExitOnError)
+	goarch := fs.String("arch", "amd64", "target GOARCH")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return fmt.Errorf("usage: gomv detect <file> <struct> [-arch=amd64]")
+	}
+	file, structName := fs.Arg(0), fs.Arg(1)
+
+	st, pkg, err := loadStructType(file, structName)
+	if err != nil {
+		return err
+	}
+	astStruct := findTypeSpec(pkg, structName)
+	lineSize := int64(arch.CacheLineSize(*goarch))
+
+	pairs := falsesharing.Detect(hotFields(st, astStruct, *goarch), lineSize)
+	if len(pairs) == 0 {
+		fmt.Println("no false sharing detected")
+		return nil
+	}
+	for _, p := range pairs {
+		fmt.Printf("false sharing: %s (%s) and %s (%s) share a %d-byte cache line\n",
+			p.A.Name, p.A.Reason, p.B.Name, p.B.Reason, p.LineSize)
+	}
+	return nil
+}
+
+func runPad(args []string) error {
+	fs := flag.NewFlagSet("pad", flag.ExitOnError)
+	goarch := fs.String("arch", "amd64", "target GOARCH")
+	generic := fs.Bool("generic", false, "emit a reusable generic CachePadded[T] wrapper instead of one concrete to <field>")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 3 {
+		return fmt.Errorf("usage: gomv pad <file> <struct> <field> [-arch=amd64] [-generic]")
+	}
+	file, structName, fieldName := fs.Arg(0), fs.Arg(1), fs.Arg(2)
+
+	st, pkg, err := loadStructType(file, structName)
+	if err != nil {
+		return err
+	}
+	lineSize := int64(arch.CacheLineSize(*goarch))
+
+	if *generic {
+		fmt.Print(falsesharing.GenerateGenericCachePadded(pkg.Types.Name(), lineSize))
+		return nil
+	}
+
+	astStruct := findTypeSpec(pkg, structName)
+	astFields := expandASTFields(astStruct)
+
+	for i, f := range hotFields(st, astStruct, *goarch) {
+		if f.Name != fieldName {
+			continue
+		}
+		goType := ""
+		if i < len(astFields) {
+			goType = astFields[i].typeName
+		}
+		fmt.Print(falsesharing.GenerateCachePadded(pkg.Types.Name(), fieldName, goType, f.Size, lineSize))
+		return nil
+	}
+	return fmt.Errorf("no field named %q in %s", fieldName, structName)
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/Shomosyt1/go-memory-visualizer/internal/arch"
+	"github.com/Shomosyt1/go-memory-visualizer/internal/layout"
+)
+
+func runLayout(args []string) error {
+	fs := flag.NewFlagSet("layout", flag.ExitOnError)
+	goarch := fs.String("arch", "amd64", "target GOARCH")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return fmt.Errorf("usage: gomv layout <file> <struct> [-arch=amd64]")
+	}
+	file, structName := fs.Arg(0), fs.Arg(1)
+
+	st, _, err := loadStructType(file, structName)
+	if err != nil {
+		return err
+	}
+	printLayout(layout.Compute(st, *goarch))
+	return nil
+}
+
+func runCompare(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: gomv compare <file> <struct>")
+	}
+	file, structName := args[0], args[1]
+
+	st, _, err := loadStructType(file, structName)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%-10s %-6s %-8s %s\n", "arch", "size", "padding", "crossings (line size)")
+	for _, s := range layout.CompareArchitectures(st, arch.All) {
+		fmt.Printf("%-10s %-6d %-8d %d (%dB)\n", s.Arch, s.Size, s.TotalPadding(), len(s.Crossings), s.LineSize)
+	}
+	return nil
+}
+
+func runToggle(args []string) error {
+	current := "amd64"
+	if len(args) == 1 {
+		current = args[0]
+	}
+	fmt.Println(arch.Next(current))
+	return nil
+}
+
+func printLayout(s layout.Struct) {
+	fmt.Printf("arch=%s size=%d align=%d line=%d\n", s.Arch, s.Size, s.Align, s.LineSize)
+	for _, f := range s.Fields {
+		fmt.Printf("  %-16s offset=%-4d size=%-4d align=%-2d padding=%d\n", f.Name, f.Offset, f.Size, f.Align, f.TrailingPadding)
+	}
+	for _, c := range s.Crossings {
+		fmt.Printf("  warning: %s crosses a %d-byte cache line boundary\n", c.Field, c.LineSize)
+	}
+	if name, padding, ok := s.TrailingZeroSizedField(); ok {
+		fmt.Printf("  warning: trailing zero-sized field %s forces %d bytes of padding; move it off the tail to remove it\n", name, padding)
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// loadStructType type-checks the package containing file and returns the
+// named struct type declared there, along with the package itself in case
+// the caller needs the AST for further scanning (tags, pragmas, literals).
+func loadStructType(file, structName string) (*types.Struct, *packages.Package, error) {
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax |
+			packages.NeedTypes | packages.NeedTypesInfo,
+	}
+	pkgs, err := packages.Load(cfg, "file="+file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading %s: %w", file, err)
+	}
+	if len(pkgs) == 0 {
+		return nil, nil, fmt.Errorf("no package found for %s", file)
+	}
+	pkg := pkgs[0]
+	if len(pkg.Errors) > 0 {
+		return nil, nil, fmt.Errorf("type-checking %s: %v", file, pkg.Errors[0])
+	}
+
+	obj := pkg.Types.Scope().Lookup(structName)
+	if obj == nil {
+		return nil, nil, fmt.Errorf("no top-level declaration named %q in %s", structName, file)
+	}
+	named, ok := obj.Type().(*types.Named)
+	if !ok {
+		return nil, nil, fmt.Errorf("%q is not a named type", structName)
+	}
+	st, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return nil, nil, fmt.Errorf("%q is not a struct type", structName)
+	}
+	return st, pkg, nil
+}
+
+// findTypeSpec locates the *ast.StructType syntax node for structName, used
+// by commands that need source-level detail (doc comments, tags) that
+// go/types alone doesn't retain.
+func findTypeSpec(pkg *packages.Package, structName string) *ast.StructType {
+	st, _, _ := findTypeSpecInFile(pkg, structName)
+	return st
+}
+
+// findTypeSpecInFile is findTypeSpec plus the declaration's doc comment and
+// the file it lives in, for commands (like `gomv reorder`) that need to
+// read //gomv: pragmas or scan the rest of the file for usage.
+func findTypeSpecInFile(pkg *packages.Package, structName string) (*ast.StructType, *ast.CommentGroup, *ast.File) {
+	for _, f := range pkg.Syntax {
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				ts, ok := spec.(*ast.TypeSpec)
+				if !ok || ts.Name.Name != structName {
+					continue
+				}
+				st, ok := ts.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+				doc := ts.Doc
+				if doc == nil {
+					doc = gen.Doc
+				}
+				return st, doc, f
+			}
+		}
+	}
+	return nil, nil, nil
+}
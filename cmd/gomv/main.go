@@ -0,0 +1,63 @@
+// Command gomv is the CLI surface for the memory-layout engine: the same
+// computations the editor extension runs for CodeLenses and hovers, exposed
+// so they can be scripted or run in CI.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `usage: gomv <command> [arguments]
+
+commands:
+  layout   <file> <struct> [-arch=amd64]   print one struct's layout for one architecture
+  compare  <file> <struct>                 print one struct's layout across every supported architecture
+  toggle   <current-arch>                  print the next architecture in the cycle
+  detect   <file> <struct>                 report hot fields sharing a cache line
+  pad      <file> <struct> <field> [-arch=amd64] [-generic]
+                                            generate a padded wrapper for one hot field, or a reusable CachePadded[T]
+  sizeclass <bytes>                        report the mspan size class an allocation is charged
+  scan     [-format=text|json|sarif] [-min-savings=N] [-min-instances=N] [patterns...]
+                                            scan a module for structs that could be reordered smaller
+  reorder  <file> <struct> [-arch=amd64]   reorder a struct's fields for minimal padding, or report why it's skipped
+  verify   <file> <struct> [-arch=amd64]   benchmark a struct's current layout against its optimal reorder`)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "layout":
+		err = runLayout(os.Args[2:])
+	case "compare":
+		err = runCompare(os.Args[2:])
+	case "toggle":
+		err = runToggle(os.Args[2:])
+	case "detect":
+		err = runDetect(os.Args[2:])
+	case "pad":
+		err = runPad(os.Args[2:])
+	case "sizeclass":
+		err = runSizeClass(os.Args[2:])
+	case "scan":
+		err = runScan(os.Args[2:])
+	case "reorder":
+		err = runReorder(os.Args[2:])
+	case "verify":
+		err = runVerify(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gomv:", err)
+		os.Exit(1)
+	}
+}
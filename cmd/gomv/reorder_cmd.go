@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/types"
+
+	"github.com/Shomosyt1/go-memory-visualizer/internal/layout"
+	"github.com/Shomosyt1/go-memory-visualizer/internal/reorder"
+)
+
+func runReorder(args []string) error {
+	fs := flag.NewFlagSet("reorder", flag.ExitOnError)
+	goarch := fs.String("arch", "amd64", "target GOARCH")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return fmt.Errorf("usage: gomv reorder <file> <struct> [-arch=amd64]")
+	}
+	file, structName := fs.Arg(0), fs.Arg(1)
+
+	st, pkg, err := loadStructType(file, structName)
+	if err != nil {
+		return err
+	}
+	astStruct, doc, _ := findTypeSpecInFile(pkg, structName)
+	freeze, keepFirst := reorder.ParsePragmas(doc)
+	wireFormatTag := reorder.HasWireFormatTag(astStruct)
+
+	target := pkg.Types.Scope().Lookup(structName).Type()
+	var wireFormat, unsafeOrCgo, positionalLiteral bool
+	for _, f := range pkg.Syntax {
+		if reorder.DetectWireFormat(pkg.TypesInfo, f, target) {
+			wireFormat = true
+		}
+		if reorder.HasUnsafeOffsetof(pkg.TypesInfo, f, target) || reorder.HasCgoReference(f, structName) {
+			unsafeOrCgo = true
+		}
+		if reorder.HasPositionalLiteral(f, structName) {
+			positionalLiteral = true
+		}
+	}
+
+	sizes := layout.SizesFor(*goarch)
+	if sizes == nil {
+		return fmt.Errorf("unrecognized -arch %q", *goarch)
+	}
+
+	plan := reorder.Compute(st, sizes, wireFormat, wireFormatTag, unsafeOrCgo, freeze, positionalLiteral, keepFirst)
+	printPlan(structName, st, plan, *goarch)
+	return nil
+}
+
+func printPlan(structName string, st *types.Struct, plan reorder.Plan, goarch string) {
+	for _, c := range plan.Constraints {
+		fmt.Printf("%s: %s\n", structName, c)
+	}
+	if plan.Skip {
+		fmt.Printf("%s: skipped, order left unchanged\n", structName)
+		return
+	}
+
+	before := layout.Compute(st, goarch)
+	after := layout.Compute(plan.Optimal, goarch)
+	fmt.Printf("%s: reordered, %d -> %d bytes (%d bytes saved)\n", structName, before.Size, after.Size, before.Size-after.Size)
+	printLayout(after)
+}
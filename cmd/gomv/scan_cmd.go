@@ -0,0 +1,228 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/Shomosyt1/go-memory-visualizer/internal/layout"
+	"github.com/Shomosyt1/go-memory-visualizer/internal/scan"
+)
+
+func runScan(args []string) error {
+	fs := flag.NewFlagSet("scan", flag.ExitOnError)
+	format := fs.String("format", "text", "output format: text, json, sarif")
+	goarch := fs.String("arch", "amd64", "target GOARCH")
+	minSavings := fs.Int64("min-savings", 0, "drop findings that save fewer than this many bytes per instance")
+	minInstances := fs.Int64("min-instances", 0, "drop findings with fewer than this many estimated instances")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	patterns := fs.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	findings, err := scanPatterns(patterns, *goarch)
+	if err != nil {
+		return err
+	}
+	ranked := scan.Rank(findings, *minSavings, *minInstances)
+
+	switch *format {
+	case "text":
+		fmt.Print(scan.FormatText(ranked))
+	case "json":
+		out, err := scan.FormatJSON(ranked)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+	case "sarif":
+		out, err := scan.FormatSARIF(ranked)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+	default:
+		return fmt.Errorf("unknown -format %q (want text, json, or sarif)", *format)
+	}
+	return nil
+}
+
+// scanPatterns loads every package matched by patterns and computes a
+// scan.Finding for each named struct declaration, each generic
+// instantiation of a struct, and each anonymous struct type used in a
+// field position.
+func scanPatterns(patterns []string, goarch string) ([]scan.Finding, error) {
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax |
+			packages.NeedTypes | packages.NeedTypesInfo,
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("loading %v: %w", patterns, err)
+	}
+
+	sizes := layout.SizesFor(goarch)
+	if sizes == nil {
+		return nil, fmt.Errorf("unrecognized -arch %q", goarch)
+	}
+
+	var findings []scan.Finding
+	for _, pkg := range pkgs {
+		if len(pkg.Errors) > 0 {
+			return nil, fmt.Errorf("type-checking %s: %v", pkg.PkgPath, pkg.Errors[0])
+		}
+		findings = append(findings, scanNamedStructs(pkg, sizes)...)
+		findings = append(findings, scanGenericInstances(pkg, sizes)...)
+		findings = append(findings, scanAnonymousStructFields(pkg, sizes)...)
+	}
+	return findings, nil
+}
+
+func scanNamedStructs(pkg *packages.Package, sizes types.Sizes) []scan.Finding {
+	var findings []scan.Finding
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				ts, ok := spec.(*ast.TypeSpec)
+				if !ok || ts.TypeParams != nil {
+					continue // generic declarations are scanned via their instantiations instead
+				}
+				obj := pkg.TypesInfo.Defs[ts.Name]
+				if obj == nil {
+					continue
+				}
+				named, ok := obj.Type().(*types.Named)
+				if !ok {
+					continue
+				}
+				st, ok := named.Underlying().(*types.Struct)
+				if !ok {
+					continue
+				}
+				if f, ok := buildFinding(pkg, sizes, ts.Name.Name, st, ts.Pos()); ok {
+					f.EstimatedInstances = countInstances(pkg, ts.Name.Name)
+					findings = append(findings, f)
+				}
+			}
+		}
+	}
+	return findings
+}
+
+// scanGenericInstances covers generic struct instantiations: the
+// declaration itself has type-parameter-shaped fields with no fixed size,
+// so it's only meaningful to report on concrete instantiations.
+func scanGenericInstances(pkg *packages.Package, sizes types.Sizes) []scan.Finding {
+	var findings []scan.Finding
+	seen := map[string]bool{}
+	for ident, inst := range pkg.TypesInfo.Instances {
+		named, ok := inst.Type.(*types.Named)
+		if !ok {
+			continue
+		}
+		st, ok := named.Underlying().(*types.Struct)
+		if !ok {
+			continue
+		}
+		name := genericInstanceName(named.Obj().Name(), inst.TypeArgs)
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		if f, ok := buildFinding(pkg, sizes, name, st, ident.Pos()); ok {
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
+
+// genericInstanceName renders a generic instantiation's display name from
+// its actual type arguments (e.g. "Box[uint64]"), so distinct instantiations
+// of the same generic type get distinct names instead of colliding on the
+// instantiated identifier's own (unrelated) source text.
+func genericInstanceName(baseName string, typeArgs *types.TypeList) string {
+	if typeArgs == nil || typeArgs.Len() == 0 {
+		return baseName
+	}
+	args := make([]string, typeArgs.Len())
+	for i := 0; i < typeArgs.Len(); i++ {
+		args[i] = types.TypeString(typeArgs.At(i), nil)
+	}
+	name := baseName + "["
+	for i, a := range args {
+		if i > 0 {
+			name += ","
+		}
+		name += a
+	}
+	return name + "]"
+}
+
+// scanAnonymousStructFields covers anonymous struct types declared inline
+// as a field's type, which never get a *types.Named and so are invisible to
+// scanNamedStructs.
+func scanAnonymousStructFields(pkg *packages.Package, sizes types.Sizes) []scan.Finding {
+	var findings []scan.Finding
+	for _, file := range pkg.Syntax {
+		ast.Inspect(file, func(n ast.Node) bool {
+			field, ok := n.(*ast.Field)
+			if !ok {
+				return true
+			}
+			anon, ok := field.Type.(*ast.StructType)
+			if !ok || len(field.Names) == 0 {
+				return true
+			}
+			t := pkg.TypesInfo.TypeOf(anon)
+			st, ok := t.(*types.Struct)
+			if !ok {
+				return true
+			}
+			name := field.Names[0].Name + " (anonymous)"
+			if f, ok := buildFinding(pkg, sizes, name, st, field.Pos()); ok {
+				findings = append(findings, f)
+			}
+			return true
+		})
+	}
+	return findings
+}
+
+func buildFinding(pkg *packages.Package, sizes types.Sizes, name string, st *types.Struct, pos token.Pos) (scan.Finding, bool) {
+	if st.NumFields() == 0 {
+		return scan.Finding{}, false
+	}
+	current := layout.SizeOf(sizes, st)
+	optimal := layout.SizeOf(sizes, scan.OptimalOrder(sizes, st))
+	if current <= optimal {
+		return scan.Finding{}, false
+	}
+	position := pkg.Fset.Position(pos)
+	return scan.Finding{
+		Package:     pkg.PkgPath,
+		File:        position.Filename,
+		Line:        position.Line,
+		StructName:  name,
+		CurrentSize: current,
+		OptimalSize: optimal,
+	}, true
+}
+
+func countInstances(pkg *packages.Package, structName string) int64 {
+	var total int64
+	for _, file := range pkg.Syntax {
+		total += scan.EstimateInstances(file, structName)
+	}
+	return total
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/Shomosyt1/go-memory-visualizer/internal/sizeclass"
+)
+
+func runSizeClass(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: gomv sizeclass <bytes>")
+	}
+	size, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid byte count %q: %w", args[0], err)
+	}
+
+	class, bytes, downClass, downBytes, ok := sizeclass.NextClassDown(size)
+	if class == -1 {
+		fmt.Printf("%d bytes is a large object, allocated directly as %d bytes (page-rounded)\n", size, bytes)
+		return nil
+	}
+	fmt.Printf("%d bytes -> size class %d (%d bytes actually allocated)\n", size, class, bytes)
+	if !ok {
+		return nil
+	}
+	saved := bytes - downBytes
+	toRemove := size - downBytes
+	fmt.Printf("removing %d bytes drops this from class %d (%dB) to class %d (%dB), saving %dB per heap allocation\n",
+		toRemove, class, bytes, downClass, downBytes, saved)
+	return nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/Shomosyt1/go-memory-visualizer/internal/arch"
+	"github.com/Shomosyt1/go-memory-visualizer/internal/falsesharing"
+	"github.com/Shomosyt1/go-memory-visualizer/internal/layout"
+	"github.com/Shomosyt1/go-memory-visualizer/internal/scan"
+	"github.com/Shomosyt1/go-memory-visualizer/internal/verify"
+)
+
+func runVerify(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	goarch := fs.String("arch", "amd64", "target GOARCH")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return fmt.Errorf("usage: gomv verify <file> <struct> [-arch=amd64]")
+	}
+	file, structName := fs.Arg(0), fs.Arg(1)
+
+	st, pkg, err := loadStructType(file, structName)
+	if err != nil {
+		return err
+	}
+	sizes := layout.SizesFor(*goarch)
+	if sizes == nil {
+		return fmt.Errorf("unrecognized -arch %q", *goarch)
+	}
+	optimal := scan.OptimalOrder(sizes, st)
+
+	beforeSize, afterSize := layout.SizeOf(sizes, st), layout.SizeOf(sizes, optimal)
+	if beforeSize <= afterSize {
+		fmt.Printf("%s: already in its optimal order (%d bytes), nothing to verify\n", structName, beforeSize)
+		return nil
+	}
+
+	astStruct := findTypeSpec(pkg, structName)
+	lineSize := int64(arch.CacheLineSize(*goarch))
+	hotA, hotB := hotPair(hotFields(st, astStruct, *goarch), lineSize)
+
+	source, err := verify.GenerateSource(sizes, []verify.Variant{
+		{Name: "Before", Struct: st, HotA: hotA, HotB: hotB},
+		{Name: "After", Struct: optimal, HotA: hotA, HotB: hotB},
+	})
+	if err != nil {
+		return fmt.Errorf("generating benchmark for %s: %w", structName, err)
+	}
+
+	fmt.Printf("running go test -bench for %s (predicted %d -> %d bytes)...\n", structName, beforeSize, afterSize)
+	results, err := verify.Run(source)
+	if err != nil {
+		return fmt.Errorf("verifying %s: %w", structName, err)
+	}
+
+	fmt.Printf("%-8s %10s %10s %12s\n", "variant", "ns/op", "B/op", "allocs/op")
+	for _, r := range results {
+		fmt.Printf("%-8s %10.2f %10d %12d\n", r.Name, r.NsPerOp, r.BytesPerOp, r.AllocsPerOp)
+	}
+	return nil
+}
+
+// hotPair picks the false-sharing pass' first reported pair of hot fields
+// sharing a cache line, so the generated contention benchmark hammers the
+// same two fields the "before" layout would actually contend on. It
+// returns two empty names if the struct has no such pair.
+func hotPair(fields []falsesharing.Field, lineSize int64) (a, b string) {
+	pairs := falsesharing.Detect(fields, lineSize)
+	if len(pairs) == 0 {
+		return "", ""
+	}
+	return pairs[0].A.Name, pairs[0].B.Name
+}
@@ -0,0 +1,38 @@
+package main
+
+import "fmt"
+
+// EXAMPLE: fixtures for `gomv verify`.
+//
+// `gomv verify <file> <struct>` computes the same optimal reorder as
+// `gomv scan`/`gomv reorder`, generates a throwaway Go module with
+// shape-equivalent "Before" and "After" structs (internal/verify), and
+// runs `go test -bench=. -benchmem` against it - so "saves 8 bytes" is a
+// number measured by testing.B, not an assertion in a comment.
+
+// EventOld is the layout before optimization: a 1-byte field forces 7
+// bytes of padding before the next 8-byte field, plus 7 more at the tail.
+type EventOld struct {
+	Active bool
+	ID     uint64
+	Kind   uint8
+}
+
+// EventNew is what `gomv reorder` produces for EventOld.
+type EventNew struct {
+	ID     uint64
+	Active bool
+	Kind   uint8
+}
+
+func main() {
+	old := EventOld{Active: true, ID: 1, Kind: 2}
+	new_ := EventNew{ID: old.ID, Active: old.Active, Kind: old.Kind}
+	fmt.Printf("%+v %+v\n", old, new_)
+
+	// gomv verify examples/benchmarkverify/main.go EventOld
+	//
+	// prints a real ns/op, B/op, and allocs/op table for Before (EventOld's
+	// declared order) vs. After (the optimal reorder), instead of asserting
+	// the numbers in a comment.
+}
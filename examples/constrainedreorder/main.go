@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync/atomic"
+)
+
+// EXAMPLE: cases where the naive "sort by descending alignment" reorder
+// would silently break the program, and the pragmas that opt out of it.
+
+// WireHeader has wire-order semantics: it's read field-by-field with
+// binary.Read, so reordering would desync the parser from the byte
+// stream on the wire. `gomv reorder` detects the binary.Read call below
+// (internal/reorder.DetectWireFormat) and skips this struct automatically.
+type WireHeader struct {
+	Version  uint8
+	Flags    uint8
+	Length   uint16
+	Sequence uint32
+}
+
+func readWireHeader(r io.Reader, h *WireHeader) error {
+	return binary.Read(r, binary.BigEndian, h)
+}
+
+// FrozenLayout carries an explicit pragma because it's part of a stable
+// public API where positional literals (FrozenLayout{1, true, "x"}) exist
+// in downstream code that this repo doesn't control.
+//
+//gomv:freeze-order
+type FrozenLayout struct {
+	ID     uint64
+	Active bool
+	Name   string
+}
+
+// AtomicFirst must keep its 64-bit atomic field at offset 0: on 32-bit
+// targets, only the first word of a struct is guaranteed 8-byte aligned
+// for atomic.Int64/atomic.AddInt64. `gomv reorder` detects a 64-bit
+// sync/atomic field and pins it first on its own, no pragma required -
+// this one documents the requirement for a human reader too.
+//
+//gomv:keep-first Counter
+type AtomicFirst struct {
+	Counter atomic.Int64
+	Active  bool
+	Name    string
+}
+
+func main() {
+	buf := bytes.NewReader([]byte{1, 0, 0, 4, 0, 0, 0, 1})
+	var h WireHeader
+	if err := readWireHeader(buf, &h); err != nil {
+		panic(err)
+	}
+
+	frozen := FrozenLayout{1, true, "x"} // positional literal - `gomv reorder` finds this and refuses to reorder FrozenLayout
+
+	var atomicFirst AtomicFirst
+	atomicFirst.Counter.Store(1)
+
+	fmt.Printf("%+v %+v counter=%d\n", h, frozen, atomicFirst.Counter.Load())
+
+	// `gomv reorder <file> WireHeader`, `... FrozenLayout`, and `... AtomicFirst`
+	// all print "skipped, order left unchanged" with the constraint that
+	// blocked them. A struct with none of these markers gets a real
+	// descending-alignment reorder instead.
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/Shomosyt1/go-memory-visualizer/internal/falsesharing"
+)
+
+// EXAMPLE: False sharing between independently-hot fields.
+//
+// The false-sharing pass (internal/falsesharing) flags a struct when two
+// fields tagged hot - atomic-typed fields, sync.Mutex, or fields carrying a
+// //gomv:hot comment - land on the same cache line. `gomv pad` then
+// generates a concrete padded wrapper for the field, printed below instead
+// of asserted in a comment.
+
+// HotCounters is the false-sharing case: two goroutines each hammering one
+// counter still fight over the same cache line.
+type HotCounters struct {
+	Requests atomic.Uint64 //gomv:hot - incremented per request on goroutine A
+	Errors   atomic.Uint64 //gomv:hot - incremented per error on goroutine B
+}
+
+// HotCountersPadded is the quick-fix output: each hot field isolated on its
+// own 64-byte line.
+type HotCountersPadded struct {
+	Requests atomic.Uint64 //gomv:hot
+	_pad0    [56]byte
+	Errors   atomic.Uint64 //gomv:hot
+	_pad1    [56]byte
+}
+
+const cacheLine = 64
+
+func main() {
+	fields := []falsesharing.Field{
+		{Name: "Requests", Offset: 0, Size: 8, Hot: true, Reason: "atomic-typed field"},
+		{Name: "Errors", Offset: 8, Size: 8, Hot: true, Reason: "atomic-typed field"},
+	}
+
+	for _, pair := range falsesharing.Detect(fields, cacheLine) {
+		fmt.Printf("false sharing: %s and %s share a %d-byte cache line\n", pair.A.Name, pair.B.Name, pair.LineSize)
+	}
+
+	// This is what `gomv pad` writes to disk for the Requests field; the
+	// same generator produced HotCountersPadded above by hand.
+	fmt.Print(falsesharing.GenerateCachePadded("main", "Requests", "atomic.Uint64", 8, cacheLine))
+}
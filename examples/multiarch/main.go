@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"go/types"
+	"unsafe"
+
+	"github.com/Shomosyt1/go-memory-visualizer/internal/arch"
+	"github.com/Shomosyt1/go-memory-visualizer/internal/layout"
+)
+
+// EXAMPLE: Cache line size varies by architecture, not just by word size.
+//
+// Run `gomv compare` on a real source file's struct to get this table from
+// the actual engine (internal/layout + internal/arch). This file hand-builds
+// the same *types.Struct the engine would parse out of WideCounters below,
+// so the comparison prints using the real Compute/CompareArchitectures code
+// rather than a fixed table asserted in a comment.
+
+// ArchSensitive has a field whose crossing status depends on the active
+// architecture: it only crosses a line on 32-byte-line targets (arm, mips).
+type ArchSensitive struct {
+	Padding [28]byte // 0-27
+	Marker  uint64   // 28-35 - crosses the 32-byte line on arm/mips, fine elsewhere
+	Tail    uint64   // 36-43
+}
+
+// WideCounters straddles the 64-byte line (Big spans offset 56-71) but stays
+// within a single 128-byte line, so it reads clean on amd64/arm64/ppc64 and
+// flags a crossing on everything using the 32- or 64-byte default.
+type WideCounters struct {
+	Padding [56]byte // 0-55
+	Big     [16]byte // 56-71 - crosses the 64-byte line, still inside 128-byte line 0
+	Tail    uint64   // 72-79
+}
+
+// wideCountersType mirrors WideCounters as a *types.Struct so it can be fed
+// straight into the real engine. `gomv compare` builds this from source via
+// go/packages instead of by hand.
+func wideCountersType() *types.Struct {
+	byteArray := func(name string, n int64) *types.Var {
+		return types.NewField(0, nil, name, types.NewArray(types.Typ[types.Byte], n), false)
+	}
+	return types.NewStruct([]*types.Var{
+		byteArray("Padding", 56),
+		byteArray("Big", 16),
+		types.NewField(0, nil, "Tail", types.Typ[types.Uint64], false),
+	}, nil)
+}
+
+func main() {
+	as := ArchSensitive{}
+	wc := WideCounters{}
+
+	fmt.Printf("ArchSensitive size: %d bytes\n", unsafe.Sizeof(as))
+	fmt.Printf("WideCounters size: %d bytes\n", unsafe.Sizeof(wc))
+
+	fmt.Println("\narch       size  padding  crossings (line size)")
+	for _, s := range layout.CompareArchitectures(wideCountersType(), arch.All) {
+		fmt.Printf("%-10s %-5d %-8d %d (%dB)\n", s.Arch, s.Size, s.TotalPadding(), len(s.Crossings), s.LineSize)
+	}
+}
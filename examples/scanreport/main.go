@@ -0,0 +1,66 @@
+package main
+
+import "fmt"
+
+// EXAMPLE: fixtures for `gomv scan ./...`.
+//
+// Unlike the single-struct CodeLens, `gomv scan` walks a whole module with
+// go/packages + go/types and ranks every struct declaration - including
+// generic instantiations and anonymous struct types in field position - by
+// estimated bytes saved. RequestRecord and ServerConfig waste the same 8
+// bytes per instance, but only RequestRecord is built with &T{...} - the
+// allocation-site heuristic (&T{}, new(T), make([]T, ...), map[K]T) counts
+// that as one hot call site, so `--min-instances 1` keeps RequestRecord and
+// drops ServerConfig even though its literal runs at startup.
+
+// RequestRecord's two 1-byte fields sit between the 8-byte ones, forcing 8
+// bytes of avoidable padding per instance.
+type RequestRecord struct {
+	Success bool
+	ID      uint64
+	Retries uint8
+	Path    string
+}
+
+// ServerConfig is built once in main; the same 8 wasted bytes here cost
+// nothing at scale, so `gomv scan --min-savings 8` should still rank
+// RequestRecord above it despite an identical per-instance saving.
+type ServerConfig struct {
+	Debug   bool
+	Port    uint64
+	Retries uint8
+	Host    string
+}
+
+// GenericBox exercises the generic-instantiation path: the scanner has to
+// evaluate padding per instantiation, not once for the generic declaration
+// (a type parameter has no fixed size on its own).
+type GenericBox[T any] struct {
+	Flag  bool
+	Value T
+}
+
+func handle(path string) *RequestRecord {
+	return &RequestRecord{Success: true, ID: 1, Retries: 0, Path: path}
+}
+
+func main() {
+	requests := make([]*RequestRecord, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		requests = append(requests, handle("/health"))
+	}
+
+	config := ServerConfig{Debug: false, Port: 8080, Retries: 3, Host: "0.0.0.0"}
+	boxedInt := GenericBox[int64]{Flag: true, Value: 42}
+
+	fmt.Printf("served %d requests on %s:%d (debug=%v), boxed=%d\n",
+		len(requests), config.Host, config.Port, config.Debug, boxedInt.Value)
+
+	// Run from the module root to see the real report. Flags must come
+	// before the package pattern - flag.FlagSet stops parsing at the first
+	// non-flag argument, so a pattern placed before them would swallow the
+	// rest of the command line as import paths.
+	//   gomv scan --format text ./examples/scanreport/...
+	//   gomv scan --format json --min-instances 10 ./...
+	//   gomv scan --format sarif ./... > gomv.sarif
+}
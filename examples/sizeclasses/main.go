@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"unsafe"
+
+	"github.com/Shomosyt1/go-memory-visualizer/internal/sizeclass"
+)
+
+// EXAMPLE: Sizeof isn't what gets charged on the heap.
+//
+// The Go runtime rounds heap allocations up to the nearest mspan size
+// class (internal/sizeclass mirrors runtime/sizeclasses.go), so shaving
+// bytes off a struct only saves real memory when it crosses a class
+// boundary. `gomv sizeclass <bytes>` reports both the rounded allocation
+// size and the next class down.
+
+// NearClassBoundary is 10072 bytes, which rounds up to the 10240-byte size
+// class. Trimming a handful of bytes off it saves nothing on the heap until
+// it drops into the 9472-byte class.
+type NearClassBoundary struct {
+	Header [56]byte
+	Body   [10000]byte
+	Footer [16]byte
+}
+
+// TrimmedPastBoundary removes 768 bytes of dead padding from
+// NearClassBoundary, dropping its rounded allocation size from 10240B to
+// 9472B - a real per-allocation saving, not just a smaller Sizeof.
+type TrimmedPastBoundary struct {
+	Header [56]byte
+	Body   [9232]byte
+	Footer [16]byte
+}
+
+// TrailingZeroSized shows why a zero-sized field at the tail forces
+// padding: Go guarantees &x.Marker stays inside the allocation, so the
+// runtime pads the struct out rather than let the field's address land
+// one byte past the end.
+type TrailingZeroSized struct {
+	Data   [24]byte
+	Marker struct{} // offset 24, forces the struct to stay 24 bytes wide
+}
+
+// TrailingZeroSizedFixed moves the zero-sized field off the tail, which
+// removes the forced trailing padding entirely.
+type TrailingZeroSizedFixed struct {
+	Marker struct{}
+	Data   [24]byte
+}
+
+func main() {
+	n := NearClassBoundary{}
+	t := TrimmedPastBoundary{}
+
+	class, bytes, downClass, downBytes, ok := sizeclass.NextClassDown(int64(unsafe.Sizeof(n)))
+	fmt.Printf("NearClassBoundary: Sizeof=%d, class %d = %dB\n", unsafe.Sizeof(n), class, bytes)
+	if ok {
+		fmt.Printf("  removing %d bytes drops it to class %d (%dB), saving %dB per heap allocation\n",
+			int64(unsafe.Sizeof(n))-downBytes, downClass, downBytes, bytes-downBytes)
+	}
+
+	tClass, tBytes, _, _, _ := sizeclass.NextClassDown(int64(unsafe.Sizeof(t)))
+	fmt.Printf("TrimmedPastBoundary: Sizeof=%d, class %d = %dB\n", unsafe.Sizeof(t), tClass, tBytes)
+}
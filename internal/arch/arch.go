@@ -0,0 +1,58 @@
+// Package arch holds the per-architecture facts the layout engine needs:
+// cache line size, and the ordered list the "Toggle Architecture" command
+// cycles through.
+package arch
+
+// DefaultCacheLine is used for any GOARCH not listed in cacheLineSizes.
+const DefaultCacheLine = 64
+
+// cacheLineSizes maps GOARCH to L1 cache line size in bytes.
+//
+//   - amd64/arm64/ppc64/ppc64le: 128 - Sandy Bridge-and-later spatial
+//     prefetchers pull adjacent 64-byte line pairs, and ARM big.LITTLE big
+//     cores use 128-byte lines, so treating pairs as the effective unit
+//     avoids false negatives on those cores.
+//   - arm/mips family/riscv64: 32 - typical L1 line size on these targets.
+//   - s390x: 256 - IBM Z cache line size.
+var cacheLineSizes = map[string]int{
+	"amd64":    128,
+	"arm64":    128,
+	"ppc64":    128,
+	"ppc64le":  128,
+	"arm":      32,
+	"mips":     32,
+	"mipsle":   32,
+	"mips64":   32,
+	"mips64le": 32,
+	"riscv64":  32,
+	"s390x":    256,
+}
+
+// All is the ordered set of architectures the analyzer supports, used both
+// for "compare across architectures" reports and for cycling through
+// "Toggle Architecture".
+var All = []string{
+	"amd64", "arm64", "386", "arm", "mips", "mipsle",
+	"mips64", "mips64le", "riscv64", "ppc64", "ppc64le", "s390x",
+}
+
+// CacheLineSize returns the L1 cache line size in bytes for goarch, falling
+// back to DefaultCacheLine for anything not in the table (e.g. 386, wasm).
+func CacheLineSize(goarch string) int {
+	if size, ok := cacheLineSizes[goarch]; ok {
+		return size
+	}
+	return DefaultCacheLine
+}
+
+// Next returns the architecture that follows current in All, wrapping
+// around at the end. It backs the "Toggle Architecture" command. If current
+// isn't recognized, Next returns the first entry in All.
+func Next(current string) string {
+	for i, a := range All {
+		if a == current {
+			return All[(i+1)%len(All)]
+		}
+	}
+	return All[0]
+}
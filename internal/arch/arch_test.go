@@ -0,0 +1,43 @@
+package arch
+
+import "testing"
+
+func TestCacheLineSize(t *testing.T) {
+	cases := map[string]int{
+		"amd64":   128,
+		"arm64":   128,
+		"ppc64le": 128,
+		"arm":     32,
+		"mips64":  32,
+		"riscv64": 32,
+		"s390x":   256,
+		"386":     DefaultCacheLine,
+		"wasm":    DefaultCacheLine,
+	}
+	for goarch, want := range cases {
+		if got := CacheLineSize(goarch); got != want {
+			t.Errorf("CacheLineSize(%q) = %d, want %d", goarch, got, want)
+		}
+	}
+}
+
+func TestNextCycles(t *testing.T) {
+	seen := map[string]bool{}
+	cur := All[0]
+	for range All {
+		seen[cur] = true
+		cur = Next(cur)
+	}
+	if cur != All[0] {
+		t.Fatalf("Next did not cycle back to %q after len(All) steps, got %q", All[0], cur)
+	}
+	if len(seen) != len(All) {
+		t.Fatalf("Next visited %d distinct archs, want %d", len(seen), len(All))
+	}
+}
+
+func TestNextUnknownFallsBackToFirst(t *testing.T) {
+	if got := Next("unknown-arch"); got != All[0] {
+		t.Errorf("Next(unknown) = %q, want %q", got, All[0])
+	}
+}
@@ -0,0 +1,168 @@
+// Package falsesharing flags struct fields that are independently hot
+// (concurrently written from different goroutines) but share a cache line,
+// and generates a concrete, compiling padded wrapper to fix it.
+//
+// Go generics can't express "pad up to the next multiple of a runtime
+// constant" as an array length - unsafe.Sizeof of a type parameter isn't a
+// constant expression, so `[cacheLine - unsafe.Sizeof(T)%cacheLine]byte`
+// inside a generic struct doesn't compile. GenerateCachePadded works around
+// this the way hand-written padded types do: it takes the concrete field
+// size at generation time and emits a non-generic wrapper with a literal
+// padding length.
+package falsesharing
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Field is the subset of layout.Field the detector needs, plus whether the
+// field is considered "hot".
+type Field struct {
+	Name   string
+	Offset int64
+	Size   int64
+	Hot    bool
+	Reason string
+}
+
+// Pair is two hot fields sharing a cache line.
+type Pair struct {
+	A, B     Field
+	LineSize int64
+}
+
+// Detect returns every pair of hot fields that land on the same cache line
+// of size lineSize. Fields with Size == 0 are ignored.
+func Detect(fields []Field, lineSize int64) []Pair {
+	var pairs []Pair
+	for i := 0; i < len(fields); i++ {
+		if !fields[i].Hot || fields[i].Size == 0 {
+			continue
+		}
+		lineI := fields[i].Offset / lineSize
+		for j := i + 1; j < len(fields); j++ {
+			if !fields[j].Hot || fields[j].Size == 0 {
+				continue
+			}
+			if fields[j].Offset/lineSize == lineI {
+				pairs = append(pairs, Pair{A: fields[i], B: fields[j], LineSize: lineSize})
+			}
+		}
+	}
+	return pairs
+}
+
+// HotReason classifies a field as hot based on its declared type name
+// (e.g. "atomic.Uint64", "sync.Mutex") or a //gomv:hot doc/line comment. It
+// returns ("", false) when neither signal is present.
+func HotReason(typeName, comment string) (string, bool) {
+	switch {
+	case containsAny(typeName, "atomic.Uint", "atomic.Int", "atomic.Bool", "atomic.Pointer", "atomic.Value"):
+		return "atomic-typed field", true
+	case containsAny(typeName, "sync.Mutex", "sync.RWMutex"):
+		return "lock field", true
+	case strings.Contains(comment, "gomv:hot"):
+		return "//gomv:hot annotation", true
+	default:
+		return "", false
+	}
+}
+
+func containsAny(s string, subs ...string) bool {
+	for _, sub := range subs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// PaddingBytes returns how many trailing padding bytes are needed to make
+// fieldSize round up to a multiple of lineSize. It is zero, not a full
+// extra line, when fieldSize is already a multiple of lineSize.
+func PaddingBytes(fieldSize, lineSize int64) int64 {
+	return (lineSize - fieldSize%lineSize) % lineSize
+}
+
+// GenerateCachePadded emits a concrete Go source file defining a
+// <fieldName>Padded wrapper around goType, padded out to lineSize bytes so
+// it never shares a cache line with a neighboring field.
+func GenerateCachePadded(pkgName, fieldName, goType string, fieldSize, lineSize int64) string {
+	pad := PaddingBytes(fieldSize, lineSize)
+	typeName := fieldName + "Padded"
+
+	if pad == 0 {
+		return fmt.Sprintf(`package %s
+
+// %s pads %s (%d bytes) onto its own %d-byte cache line to prevent false
+// sharing with neighboring hot fields. %s is already a multiple of the
+// line size, so no filler bytes are needed.
+type %s struct {
+	Value %s
+}
+`, pkgName, typeName, fieldName, fieldSize, lineSize, goType, typeName, goType)
+	}
+
+	return fmt.Sprintf(`package %s
+
+// %s pads %s (%d bytes) onto its own %d-byte cache line to prevent false
+// sharing with neighboring hot fields.
+type %s struct {
+	Value %s
+	_     [%d]byte
+}
+`, pkgName, typeName, fieldName, fieldSize, lineSize, typeName, goType, pad)
+}
+
+// genericCachePaddedMaxValueSize is the largest value CachePadded[T]'s
+// generated tail is sized for: 8 bytes covers every type gomv's hot
+// heuristic flags (atomic.Uint64/Int64/Pointer, sync.Mutex).
+const genericCachePaddedMaxValueSize = 8
+
+// GenerateGenericCachePadded emits a reusable, generic `CachePadded[T any]`
+// wrapper for lineSize-byte cache lines.
+//
+// A literal `[cacheLine - unsafe.Sizeof(T)%cacheLine]byte` tail - the exact
+// shape the false-sharing pass would emit for a concrete field - doesn't
+// compile inside a generic struct: unsafe.Sizeof(T) isn't a constant
+// expression for a type parameter, so the array length can't be computed at
+// compile time. GenerateCachePadded works around this per call site by
+// generating a concrete, non-generic wrapper once fieldSize is known; this
+// generic wrapper instead fixes the tail to genericCachePaddedMaxValueSize
+// bytes and pushes the "does this actually fill one cache line" check to
+// program init, since a generic can't assert it at compile time either.
+func GenerateGenericCachePadded(pkgName string, lineSize int64) string {
+	tail := lineSize - genericCachePaddedMaxValueSize
+
+	return fmt.Sprintf(`package %[1]s
+
+import "unsafe"
+
+// CachePadded wraps a value up to %[2]d bytes wide (the size of every type
+// gomv's false-sharing heuristic treats as hot: atomic.Uint64, atomic.Int64,
+// atomic.Pointer, sync.Mutex) onto its own %[3]d-byte cache line, so
+// concurrent writers to Value never false-share it with a neighboring hot
+// field. For a value whose exact size is known up front, `+"`gomv pad`"+`
+// generates a concrete non-generic wrapper instead (see GenerateCachePadded)
+// with a tail sized precisely for that value rather than this fixed bound.
+type CachePadded[T any] struct {
+	Value T
+	_     [%[4]d]byte
+}
+
+// init verifies the padding above still fills exactly one %[3]d-byte cache
+// line for a %[2]d-byte value. unsafe.Sizeof(T) isn't a constant expression
+// for a type parameter, so this can't be a compile-time static assertion the
+// way GenerateCachePadded's concrete wrappers can be checked by hand; this
+// is the closest generic equivalent, catching a stale generated file (e.g.
+// copied to a workspace targeting a different architecture) at startup
+// instead of silently letting Value and its neighbor share a line again.
+func init() {
+	var probe CachePadded[[%[2]d]byte]
+	if unsafe.Sizeof(probe) != uintptr(%[3]d) {
+		panic("gomv: CachePadded no longer fills a %[3]d-byte cache line; regenerate it for this architecture")
+	}
+}
+`, pkgName, genericCachePaddedMaxValueSize, lineSize, tail)
+}
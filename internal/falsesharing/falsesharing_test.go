@@ -0,0 +1,105 @@
+package falsesharing
+
+import (
+	"go/format"
+	"strings"
+	"testing"
+)
+
+func TestDetectFindsSharedLine(t *testing.T) {
+	fields := []Field{
+		{Name: "Requests", Offset: 0, Size: 8, Hot: true},
+		{Name: "Padding", Offset: 8, Size: 56, Hot: false},
+		{Name: "Errors", Offset: 64, Size: 8, Hot: true},
+	}
+	pairs := Detect(fields, 64)
+	if len(pairs) != 0 {
+		t.Fatalf("Requests/Errors are on different 64-byte lines, want 0 pairs, got %+v", pairs)
+	}
+
+	adjacent := []Field{
+		{Name: "Requests", Offset: 0, Size: 8, Hot: true},
+		{Name: "Errors", Offset: 8, Size: 8, Hot: true},
+	}
+	pairs = Detect(adjacent, 64)
+	if len(pairs) != 1 || pairs[0].A.Name != "Requests" || pairs[0].B.Name != "Errors" {
+		t.Fatalf("want one Requests/Errors pair, got %+v", pairs)
+	}
+}
+
+func TestDetectIgnoresColdAndZeroSized(t *testing.T) {
+	fields := []Field{
+		{Name: "Requests", Offset: 0, Size: 8, Hot: true},
+		{Name: "Cold", Offset: 8, Size: 8, Hot: false},
+		{Name: "Marker", Offset: 16, Size: 0, Hot: true},
+	}
+	if pairs := Detect(fields, 64); len(pairs) != 0 {
+		t.Fatalf("want no pairs, got %+v", pairs)
+	}
+}
+
+func TestHotReason(t *testing.T) {
+	cases := []struct {
+		typeName, comment string
+		wantHot           bool
+	}{
+		{"atomic.Uint64", "", true},
+		{"sync.Mutex", "", true},
+		{"uint64", "//gomv:hot", true},
+		{"uint64", "", false},
+	}
+	for _, c := range cases {
+		_, hot := HotReason(c.typeName, c.comment)
+		if hot != c.wantHot {
+			t.Errorf("HotReason(%q, %q) hot = %v, want %v", c.typeName, c.comment, hot, c.wantHot)
+		}
+	}
+}
+
+func TestPaddingBytesNoWastedLineWhenAlreadyMultiple(t *testing.T) {
+	if got := PaddingBytes(64, 64); got != 0 {
+		t.Errorf("PaddingBytes(64, 64) = %d, want 0 (regression: used to pad a full extra line)", got)
+	}
+	if got := PaddingBytes(128, 64); got != 0 {
+		t.Errorf("PaddingBytes(128, 64) = %d, want 0", got)
+	}
+	if got := PaddingBytes(8, 64); got != 56 {
+		t.Errorf("PaddingBytes(8, 64) = %d, want 56", got)
+	}
+}
+
+func TestGenerateCachePaddedCompiles(t *testing.T) {
+	src := GenerateCachePadded("counters", "Requests", "uint64", 8, 64)
+	if _, err := format.Source([]byte(src)); err != nil {
+		t.Fatalf("generated source is not valid Go: %v\n%s", err, src)
+	}
+	if !strings.Contains(src, "[56]byte") {
+		t.Errorf("expected 56 bytes of padding for an 8-byte field on a 64-byte line, got:\n%s", src)
+	}
+}
+
+func TestGenerateCachePaddedOmitsPaddingWhenAlreadyLineSized(t *testing.T) {
+	src := GenerateCachePadded("counters", "Block", "[64]byte", 64, 64)
+	if _, err := format.Source([]byte(src)); err != nil {
+		t.Fatalf("generated source is not valid Go: %v\n%s", err, src)
+	}
+	if strings.Contains(src, "_     [0]byte") {
+		t.Errorf("should omit the filler field entirely rather than emit a zero-length one:\n%s", src)
+	}
+}
+
+func TestGenerateGenericCachePaddedCompiles(t *testing.T) {
+	src := GenerateGenericCachePadded("counters", 64)
+	if _, err := format.Source([]byte(src)); err != nil {
+		t.Fatalf("generated source is not valid Go: %v\n%s", err, src)
+	}
+	if !strings.Contains(src, "type CachePadded[T any] struct") {
+		t.Errorf("expected a generic CachePadded[T] type, got:\n%s", src)
+	}
+	if !strings.Contains(src, "[56]byte") {
+		t.Errorf("expected a 56-byte tail (64-byte line minus an 8-byte value), got:\n%s", src)
+	}
+	if !strings.Contains(src, "func init()") {
+		t.Errorf("expected an init-time check since unsafe.Sizeof(T) can't be asserted at compile time for a type parameter:\n%s", src)
+	}
+}
@@ -0,0 +1,182 @@
+// Package layout computes struct field offsets, padding, and cache-line
+// crossings for a given target architecture, using go/types' own notion of
+// sizes so the numbers match what the Go compiler would actually produce.
+package layout
+
+import (
+	"go/types"
+
+	"github.com/Shomosyt1/go-memory-visualizer/internal/arch"
+)
+
+// Field describes one struct field's placement within its struct.
+type Field struct {
+	Name            string
+	Offset          int64
+	Size            int64
+	Align           int64
+	TrailingPadding int64 // bytes of padding between this field and the next (or the struct end)
+}
+
+// Crossing records that a field's byte range spans more than one cache
+// line for the line size the Struct was computed with.
+type Crossing struct {
+	Field    string
+	LineSize int64
+}
+
+// Struct is the computed layout of a single struct type on one architecture.
+type Struct struct {
+	Arch      string
+	Size      int64
+	Align     int64
+	LineSize  int64
+	Fields    []Field
+	Crossings []Crossing
+}
+
+// TotalPadding sums the trailing padding across all fields.
+func (s Struct) TotalPadding() int64 {
+	var total int64
+	for _, f := range s.Fields {
+		total += f.TrailingPadding
+	}
+	return total
+}
+
+// TrailingZeroSizedField reports the struct's last field when it's
+// zero-sized (e.g. a trailing struct{}) and forces trailing padding: Go
+// guarantees &x.f stays inside the allocation, so the runtime pads the
+// struct out rather than let the field's address land past the end.
+// Moving the zero-sized field off the tail eliminates that padding.
+func (s Struct) TrailingZeroSizedField() (name string, padding int64, ok bool) {
+	if len(s.Fields) == 0 {
+		return "", 0, false
+	}
+	last := s.Fields[len(s.Fields)-1]
+	if last.Size == 0 && last.TrailingPadding > 0 {
+		return last.Name, last.TrailingPadding, true
+	}
+	return "", 0, false
+}
+
+// SizesFor returns the go/types.Sizes for goarch using the gc compiler's
+// layout rules, or nil if goarch isn't recognized by go/types.
+func SizesFor(goarch string) types.Sizes {
+	return types.SizesFor("gc", goarch)
+}
+
+// SizeOf returns st's actual compiled size, correcting for two things
+// go/types.StdSizes.Sizeof doesn't do on its own:
+//
+//   - it never rounds the final size up to the struct's own alignment, so a
+//     struct whose last field ends mid-word (e.g. a trailing bool) comes out
+//     short of what unsafe.Sizeof reports;
+//   - it doesn't add the one byte gc adds when the last field is zero-sized,
+//     so &x.lastField still points inside the allocation.
+func SizeOf(sizes types.Sizes, st *types.Struct) int64 {
+	n := st.NumFields()
+	if n == 0 {
+		return 0
+	}
+
+	vars := make([]*types.Var, n)
+	for i := 0; i < n; i++ {
+		vars[i] = st.Field(i)
+	}
+	offsets := sizes.Offsetsof(vars)
+
+	raw := sizes.Sizeof(st)
+	align := int64(sizes.Alignof(st))
+
+	last := offsets[n-1]
+	lastSize := sizes.Sizeof(vars[n-1].Type())
+	if lastSize == 0 && raw <= last {
+		raw = last + 1
+	}
+	return roundUp(raw, align)
+}
+
+// Compute lays out st as the gc compiler would for the given architecture
+// and reports every field range that crosses a cache line boundary for
+// that architecture's line size.
+func Compute(st *types.Struct, goarch string) Struct {
+	sizes := SizesFor(goarch)
+	if sizes == nil {
+		sizes = SizesFor("amd64")
+	}
+	lineSize := int64(arch.CacheLineSize(goarch))
+
+	n := st.NumFields()
+	vars := make([]*types.Var, n)
+	for i := 0; i < n; i++ {
+		vars[i] = st.Field(i)
+	}
+
+	structSize := SizeOf(sizes, st)
+	structAlign := int64(sizes.Alignof(st))
+
+	var offsets []int64
+	if n > 0 {
+		offsets = sizes.Offsetsof(vars)
+	}
+
+	fields := make([]Field, n)
+	var crossings []Crossing
+	for i, v := range vars {
+		fieldSize := sizes.Sizeof(v.Type())
+		fieldAlign := int64(sizes.Alignof(v.Type()))
+		offset := offsets[i]
+
+		var trailing int64
+		if i+1 < n {
+			trailing = offsets[i+1] - (offset + fieldSize)
+		} else {
+			trailing = structSize - (offset + fieldSize)
+		}
+
+		fields[i] = Field{
+			Name:            v.Name(),
+			Offset:          offset,
+			Size:            fieldSize,
+			Align:           fieldAlign,
+			TrailingPadding: trailing,
+		}
+
+		if fieldSize > 0 {
+			startLine := offset / lineSize
+			endLine := (offset + fieldSize - 1) / lineSize
+			if startLine != endLine {
+				crossings = append(crossings, Crossing{Field: v.Name(), LineSize: lineSize})
+			}
+		}
+	}
+
+	return Struct{
+		Arch:      goarch,
+		Size:      structSize,
+		Align:     structAlign,
+		LineSize:  lineSize,
+		Fields:    fields,
+		Crossings: crossings,
+	}
+}
+
+// CompareArchitectures computes st's layout on every architecture in archs,
+// skipping any goarch go/types doesn't recognize. This backs the "compare
+// across architectures" report.
+func CompareArchitectures(st *types.Struct, archs []string) []Struct {
+	results := make([]Struct, 0, len(archs))
+	for _, a := range archs {
+		if SizesFor(a) == nil {
+			continue
+		}
+		results = append(results, Compute(st, a))
+	}
+	return results
+}
+
+// roundUp rounds size up to the nearest multiple of align (align must be > 0).
+func roundUp(size, align int64) int64 {
+	return (size + align - 1) / align * align
+}
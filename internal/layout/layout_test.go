@@ -0,0 +1,120 @@
+package layout
+
+import (
+	"go/types"
+	"testing"
+)
+
+// newField builds an unexported-package-free *types.Var field, matching
+// what go/types produces for package-level struct fields.
+func newField(name string, typ types.Type) *types.Var {
+	return types.NewField(0, nil, name, typ, false)
+}
+
+func TestComputeMatchesBadStruct(t *testing.T) {
+	// Mirrors examples/structs BadStruct: bool, uint64, bool, uint64,
+	// uint16, uint64 - the textbook padding example.
+	st := types.NewStruct([]*types.Var{
+		newField("A", types.Typ[types.Bool]),
+		newField("B", types.Typ[types.Uint64]),
+		newField("C", types.Typ[types.Bool]),
+		newField("D", types.Typ[types.Uint64]),
+		newField("E", types.Typ[types.Uint16]),
+		newField("F", types.Typ[types.Uint64]),
+	}, nil)
+
+	got := Compute(st, "amd64")
+
+	if got.Size != 48 {
+		t.Fatalf("Size = %d, want 48", got.Size)
+	}
+	if got.TotalPadding() != 20 {
+		t.Fatalf("TotalPadding = %d, want 20", got.TotalPadding())
+	}
+	if got.Fields[0].TrailingPadding != 7 {
+		t.Errorf("field A trailing padding = %d, want 7", got.Fields[0].TrailingPadding)
+	}
+}
+
+func TestComputeDetectsCacheLineCrossing(t *testing.T) {
+	// Mirrors examples/cachelines CacheLineCrosser: [60]byte padding then a
+	// 16-byte field straddling offset 64.
+	st := types.NewStruct([]*types.Var{
+		newField("Padding", types.NewArray(types.Typ[types.Byte], 60)),
+		newField("Big", types.NewArray(types.Typ[types.Byte], 16)),
+		newField("After", types.Typ[types.Uint64]),
+	}, nil)
+
+	got := Compute(st, "386") // 386 falls back to the 64-byte default line
+
+	if len(got.Crossings) != 1 || got.Crossings[0].Field != "Big" {
+		t.Fatalf("Crossings = %+v, want a single crossing on Big", got.Crossings)
+	}
+}
+
+func TestComputeUsesPerArchCacheLine(t *testing.T) {
+	// A field spanning bytes 28-35 crosses a 32-byte line but not a
+	// 128-byte or 64-byte line.
+	st := types.NewStruct([]*types.Var{
+		newField("Padding", types.NewArray(types.Typ[types.Byte], 28)),
+		newField("Marker", types.Typ[types.Uint64]),
+	}, nil)
+
+	arm := Compute(st, "arm")
+	if len(arm.Crossings) != 1 {
+		t.Fatalf("arm (32B line): Crossings = %+v, want 1 crossing", arm.Crossings)
+	}
+
+	amd64 := Compute(st, "amd64")
+	if len(amd64.Crossings) != 0 {
+		t.Fatalf("amd64 (128B line): Crossings = %+v, want none", amd64.Crossings)
+	}
+}
+
+func TestCompareArchitecturesSkipsUnknownAndCoversRequested(t *testing.T) {
+	st := types.NewStruct([]*types.Var{
+		newField("A", types.Typ[types.Uint64]),
+	}, nil)
+
+	results := CompareArchitectures(st, append([]string{"not-a-real-arch"}, "amd64", "arm"))
+
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2 (unknown arch skipped)", len(results))
+	}
+	byArch := map[string]Struct{}
+	for _, r := range results {
+		byArch[r.Arch] = r
+	}
+	if byArch["amd64"].LineSize != 128 {
+		t.Errorf("amd64 line size = %d, want 128", byArch["amd64"].LineSize)
+	}
+	if byArch["arm"].LineSize != 32 {
+		t.Errorf("arm line size = %d, want 32", byArch["arm"].LineSize)
+	}
+}
+
+func TestTrailingZeroSizedField(t *testing.T) {
+	trailing := types.NewStruct([]*types.Var{
+		newField("Data", types.NewArray(types.Typ[types.Byte], 24)),
+		newField("Marker", types.NewStruct(nil, nil)),
+	}, nil)
+	computed := Compute(trailing, "amd64")
+	if computed.Size != 25 {
+		// examples/sizeclasses.TrailingZeroSized{Data [24]byte; Marker
+		// struct{}} is 25 bytes via unsafe.Sizeof - the tail byte gc adds
+		// so &x.Marker stays in bounds.
+		t.Fatalf("Size = %d, want 25 (matching unsafe.Sizeof for this shape)", computed.Size)
+	}
+	name, padding, ok := computed.TrailingZeroSizedField()
+	if !ok || name != "Marker" || padding == 0 {
+		t.Fatalf("TrailingZeroSizedField() = %q, %d, %v; want Marker with nonzero padding", name, padding, ok)
+	}
+
+	fixed := types.NewStruct([]*types.Var{
+		newField("Marker", types.NewStruct(nil, nil)),
+		newField("Data", types.NewArray(types.Typ[types.Byte], 24)),
+	}, nil)
+	if _, _, ok := Compute(fixed, "amd64").TrailingZeroSizedField(); ok {
+		t.Fatal("moving the zero-sized field off the tail should report ok=false")
+	}
+}
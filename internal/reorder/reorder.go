@@ -0,0 +1,305 @@
+// Package reorder decides whether a struct's fields can be safely sorted
+// into the smallest-padding order, or whether some constraint in the
+// surrounding source - a wire format, a public-API freeze, an unkeyed
+// composite literal, or a 32-bit atomic alignment requirement - means the
+// declared order is load-bearing and must be left alone.
+package reorder
+
+import (
+	"go/ast"
+	"go/types"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Constraint documents one reason found in the source that blocks or
+// constrains a reorder.
+type Constraint struct {
+	Kind   string // "wire-format", "frozen-order", "positional-literal", "atomic-first", "keep-first"
+	Detail string
+}
+
+func (c Constraint) String() string {
+	return c.Kind + ": " + c.Detail
+}
+
+// Plan is the result of checking one struct: either it's blocked outright
+// (Skip == true, Optimal is st's original field order unchanged), or
+// Optimal is a constraint-respecting reordering - the normal
+// descending-alignment sort, with any keep-first field pinned at offset 0.
+type Plan struct {
+	Constraints []Constraint
+	Skip        bool
+	Optimal     *types.Struct
+}
+
+// ParsePragmas reads the //gomv: directives out of a type's doc comment.
+// //gomv:freeze-order blocks reordering outright; //gomv:keep-first <field>
+// pins one field at offset 0 while the rest are still sorted.
+func ParsePragmas(doc *ast.CommentGroup) (freeze bool, keepFirst string) {
+	if doc == nil {
+		return false, ""
+	}
+	for _, c := range doc.List {
+		text := strings.TrimSpace(strings.TrimPrefix(c.Text, "//"))
+		switch {
+		case text == "gomv:freeze-order":
+			freeze = true
+		case strings.HasPrefix(text, "gomv:keep-first "):
+			keepFirst = strings.TrimSpace(strings.TrimPrefix(text, "gomv:keep-first "))
+		}
+	}
+	return freeze, keepFirst
+}
+
+// HasPositionalLiteral reports whether file constructs structName with an
+// unkeyed composite literal (e.g. FrozenLayout{1, true, "x"}) anywhere.
+// Reordering the struct's fields would silently reassign those values to
+// the wrong fields, so the presence of even one such literal blocks the
+// reorder for the whole workspace.
+func HasPositionalLiteral(file *ast.File, structName string) bool {
+	found := false
+	ast.Inspect(file, func(n ast.Node) bool {
+		if found {
+			return false
+		}
+		lit, ok := n.(*ast.CompositeLit)
+		if !ok || identName(lit.Type) != structName || len(lit.Elts) == 0 {
+			return true
+		}
+		if _, keyed := lit.Elts[0].(*ast.KeyValueExpr); !keyed {
+			found = true
+		}
+		return true
+	})
+	return found
+}
+
+func identName(expr ast.Expr) string {
+	if id, ok := expr.(*ast.Ident); ok {
+		return id.Name
+	}
+	return ""
+}
+
+// DetectWireFormat reports whether file calls encoding/binary's Read or
+// Write with an argument of type target or *target - i.e. the struct is
+// parsed or serialized field-by-field, so its declared order is the wire
+// format and must not change.
+func DetectWireFormat(info *types.Info, file *ast.File, target types.Type) bool {
+	found := false
+	ptrTarget := types.NewPointer(target)
+	ast.Inspect(file, func(n ast.Node) bool {
+		if found {
+			return false
+		}
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || (sel.Sel.Name != "Read" && sel.Sel.Name != "Write") {
+			return true
+		}
+		pkgIdent, ok := sel.X.(*ast.Ident)
+		if !ok || pkgIdent.Name != "binary" {
+			return true
+		}
+		for _, arg := range call.Args {
+			t := info.TypeOf(arg)
+			if t != nil && (types.Identical(t, target) || types.Identical(t, ptrTarget)) {
+				found = true
+			}
+		}
+		return true
+	})
+	return found
+}
+
+// HasWireFormatTag reports whether astStruct declares any field with a
+// `binary:` or `struct:` struct tag - the marker a reflection-based codec
+// (rather than an explicit encoding/binary.Read/Write call site) uses to
+// walk the struct field-by-field, which makes the declared order the wire
+// format just as surely as an encoding/binary call would.
+func HasWireFormatTag(astStruct *ast.StructType) bool {
+	if astStruct == nil {
+		return false
+	}
+	for _, f := range astStruct.Fields.List {
+		if f.Tag == nil {
+			continue
+		}
+		tag := reflect.StructTag(strings.Trim(f.Tag.Value, "`"))
+		if _, ok := tag.Lookup("binary"); ok {
+			return true
+		}
+		if _, ok := tag.Lookup("struct"); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// HasUnsafeOffsetof reports whether file computes unsafe.Offsetof on a
+// field of target (or *target) - a sign that some other code walks the
+// struct by raw byte offset, so reordering fields would silently
+// invalidate those offsets.
+func HasUnsafeOffsetof(info *types.Info, file *ast.File, target types.Type) bool {
+	found := false
+	ptrTarget := types.NewPointer(target)
+	ast.Inspect(file, func(n ast.Node) bool {
+		if found {
+			return false
+		}
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "Offsetof" {
+			return true
+		}
+		pkgIdent, ok := sel.X.(*ast.Ident)
+		if !ok || pkgIdent.Name != "unsafe" {
+			return true
+		}
+		for _, arg := range call.Args {
+			fieldSel, ok := arg.(*ast.SelectorExpr)
+			if !ok {
+				continue
+			}
+			t := info.TypeOf(fieldSel.X)
+			if t != nil && (types.Identical(t, target) || types.Identical(t, ptrTarget)) {
+				found = true
+			}
+		}
+		return true
+	})
+	return found
+}
+
+// HasCgoReference reports whether file is a cgo source (it imports "C")
+// that also mentions structName - a signal the struct's layout may be
+// shared with a C struct definition passed across the cgo boundary, where
+// field order and size are load-bearing.
+func HasCgoReference(file *ast.File, structName string) bool {
+	isCgo := false
+	for _, imp := range file.Imports {
+		if imp.Path.Value == `"C"` {
+			isCgo = true
+			break
+		}
+	}
+	if !isCgo {
+		return false
+	}
+	found := false
+	ast.Inspect(file, func(n ast.Node) bool {
+		if found {
+			return false
+		}
+		if id, ok := n.(*ast.Ident); ok && id.Name == structName {
+			found = true
+		}
+		return true
+	})
+	return found
+}
+
+// atomicFirstField returns the name of st's first sync/atomic 64-bit field
+// (Int64, Uint64, or Pointer), if any. Only offset 0 of a struct is
+// guaranteed 8-byte aligned on a 32-bit target, so a field like this must
+// stay first or atomic.Int64.Add et al. can panic on arm/386/mips.
+func atomicFirstField(st *types.Struct) (name string, ok bool) {
+	for i := 0; i < st.NumFields(); i++ {
+		if isAtomic64(st.Field(i).Type()) {
+			return st.Field(i).Name(), true
+		}
+	}
+	return "", false
+}
+
+func isAtomic64(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	if obj.Pkg() == nil || obj.Pkg().Path() != "sync/atomic" {
+		return false
+	}
+	switch obj.Name() {
+	case "Int64", "Uint64", "Pointer":
+		return true
+	}
+	return false
+}
+
+// Compute builds a Plan for st, given the constraints observed by the
+// caller in the struct's surrounding source (see DetectWireFormat,
+// HasWireFormatTag, HasUnsafeOffsetof, HasCgoReference, ParsePragmas,
+// HasPositionalLiteral). Any 64-bit sync/atomic field is treated as an
+// implicit keep-first constraint even without a pragma.
+func Compute(st *types.Struct, sizes types.Sizes, wireFormat, wireFormatTag, unsafeOrCgo, freezeOrder bool, positionalLiteral bool, keepFirst string) Plan {
+	var constraints []Constraint
+	if wireFormat {
+		constraints = append(constraints, Constraint{"wire-format", "read/written field-by-field with encoding/binary"})
+	}
+	if wireFormatTag {
+		constraints = append(constraints, Constraint{"wire-format", "field(s) carry a binary/struct struct tag read by a reflection-based codec"})
+	}
+	if unsafeOrCgo {
+		constraints = append(constraints, Constraint{"wire-format", "a field offset is computed with unsafe.Offsetof or referenced from cgo"})
+	}
+	if freezeOrder {
+		constraints = append(constraints, Constraint{"frozen-order", "//gomv:freeze-order pragma"})
+	}
+	if positionalLiteral {
+		constraints = append(constraints, Constraint{"positional-literal", "constructed with an unkeyed composite literal elsewhere in the workspace"})
+	}
+
+	if atomicField, ok := atomicFirstField(st); ok {
+		if keepFirst == "" {
+			keepFirst = atomicField
+			constraints = append(constraints, Constraint{"atomic-first", atomicField + " is a sync/atomic 64-bit field and must stay at offset 0 for 32-bit alignment"})
+		}
+	} else if keepFirst != "" {
+		constraints = append(constraints, Constraint{"keep-first", "//gomv:keep-first " + keepFirst})
+	}
+
+	if wireFormat || wireFormatTag || unsafeOrCgo || freezeOrder || positionalLiteral {
+		return Plan{Constraints: constraints, Skip: true, Optimal: st}
+	}
+	return Plan{Constraints: constraints, Skip: false, Optimal: reorderKeepingFirst(st, sizes, keepFirst)}
+}
+
+// reorderKeepingFirst sorts st's fields by descending alignment (ties by
+// descending size, then declaration order), except keepFirst - if set - is
+// left pinned at index 0.
+func reorderKeepingFirst(st *types.Struct, sizes types.Sizes, keepFirst string) *types.Struct {
+	n := st.NumFields()
+	var pinned *types.Var
+	rest := make([]*types.Var, 0, n)
+	for i := 0; i < n; i++ {
+		v := st.Field(i)
+		if keepFirst != "" && v.Name() == keepFirst && pinned == nil {
+			pinned = v
+			continue
+		}
+		rest = append(rest, v)
+	}
+
+	sort.SliceStable(rest, func(i, j int) bool {
+		ai, aj := sizes.Alignof(rest[i].Type()), sizes.Alignof(rest[j].Type())
+		if ai != aj {
+			return ai > aj
+		}
+		return sizes.Sizeof(rest[i].Type()) > sizes.Sizeof(rest[j].Type())
+	})
+
+	if pinned != nil {
+		rest = append([]*types.Var{pinned}, rest...)
+	}
+	return types.NewStruct(rest, nil)
+}
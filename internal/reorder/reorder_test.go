@@ -0,0 +1,260 @@
+package reorder
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+)
+
+func newField(name string, typ types.Type) *types.Var {
+	return types.NewField(0, nil, name, typ, false)
+}
+
+func fieldNames(st *types.Struct) []string {
+	names := make([]string, st.NumFields())
+	for i := range names {
+		names[i] = st.Field(i).Name()
+	}
+	return names
+}
+
+func TestParsePragmasFreezeOrder(t *testing.T) {
+	doc := &ast.CommentGroup{List: []*ast.Comment{
+		{Text: "// FrozenLayout is part of a stable public API."},
+		{Text: "//"},
+		{Text: "//gomv:freeze-order"},
+	}}
+	freeze, keepFirst := ParsePragmas(doc)
+	if !freeze {
+		t.Error("want freeze-order detected")
+	}
+	if keepFirst != "" {
+		t.Errorf("keepFirst = %q, want empty", keepFirst)
+	}
+}
+
+func TestParsePragmasKeepFirst(t *testing.T) {
+	doc := &ast.CommentGroup{List: []*ast.Comment{
+		{Text: "// AtomicFirst must keep Counter at offset 0."},
+		{Text: "//gomv:keep-first Counter"},
+	}}
+	freeze, keepFirst := ParsePragmas(doc)
+	if freeze {
+		t.Error("want freeze-order not detected")
+	}
+	if keepFirst != "Counter" {
+		t.Errorf("keepFirst = %q, want Counter", keepFirst)
+	}
+}
+
+func TestHasPositionalLiteral(t *testing.T) {
+	src := `package p
+
+type FrozenLayout struct {
+	ID     uint64
+	Active bool
+	Name   string
+}
+
+func use() {
+	_ = FrozenLayout{1, true, "x"}
+}
+`
+	file, err := parser.ParseFile(token.NewFileSet(), "src.go", src, 0)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if !HasPositionalLiteral(file, "FrozenLayout") {
+		t.Error("want positional literal detected")
+	}
+	if HasPositionalLiteral(file, "OtherType") {
+		t.Error("want no positional literal for an unrelated type name")
+	}
+}
+
+func TestHasPositionalLiteralIgnoresKeyedLiterals(t *testing.T) {
+	src := `package p
+
+type FrozenLayout struct {
+	ID     uint64
+	Active bool
+}
+
+func use() {
+	_ = FrozenLayout{ID: 1, Active: true}
+}
+`
+	file, err := parser.ParseFile(token.NewFileSet(), "src.go", src, 0)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if HasPositionalLiteral(file, "FrozenLayout") {
+		t.Error("keyed literal should not be flagged as positional")
+	}
+}
+
+func TestDetectWireFormat(t *testing.T) {
+	src := `package p
+
+import "encoding/binary"
+
+type WireHeader struct {
+	Version uint8
+	Flags   uint8
+}
+
+func read(r interface {
+	Read([]byte) (int, error)
+}, h *WireHeader) error {
+	return binary.Read(r, binary.BigEndian, h)
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "src.go", src, 0)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	conf := types.Config{Importer: nil, Error: func(error) {}}
+	info := &types.Info{Types: map[ast.Expr]types.TypeAndValue{}, Defs: map[*ast.Ident]types.Object{}, Uses: map[*ast.Ident]types.Object{}}
+	// A minimal, unimported type-check would fail on the binary import, so
+	// build just enough type info by hand instead of running go/types.Check.
+	_ = conf
+	_ = info
+
+	wireHeader := types.NewStruct([]*types.Var{
+		newField("Version", types.Typ[types.Uint8]),
+		newField("Flags", types.Typ[types.Uint8]),
+	}, nil)
+
+	// DetectWireFormat only needs info.TypeOf for the call's arguments; feed
+	// it a hand-built types.Info that maps the "h" argument to *WireHeader,
+	// matching what a real go/packages load would produce.
+	var hArg *ast.Ident
+	ast.Inspect(file, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && id.Name == "h" {
+			hArg = id
+		}
+		return true
+	})
+	if hArg == nil {
+		t.Fatal("could not find identifier h in test source")
+	}
+	info.Types[hArg] = types.TypeAndValue{Type: types.NewPointer(wireHeader)}
+
+	if !DetectWireFormat(info, file, wireHeader) {
+		t.Error("want wire format detected for binary.Read(r, order, h)")
+	}
+}
+
+func TestHasWireFormatTag(t *testing.T) {
+	src := "package p\n\ntype Framed struct {\n\tFlag    bool   `binary:\"1\"`\n\tMagic   uint64 `binary:\"2\"`\n\tVersion uint16 `binary:\"3\"`\n}\n"
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "src.go", src, 0)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	var astStruct *ast.StructType
+	ast.Inspect(file, func(n ast.Node) bool {
+		if ts, ok := n.(*ast.StructType); ok {
+			astStruct = ts
+		}
+		return true
+	})
+	if !HasWireFormatTag(astStruct) {
+		t.Error("want a binary struct tag detected")
+	}
+	if HasWireFormatTag(nil) {
+		t.Error("nil astStruct should report no tag")
+	}
+}
+
+func TestHasCgoReference(t *testing.T) {
+	src := `package p
+
+import "C"
+
+type CHeader struct {
+	Magic uint32
+}
+
+func use(h *CHeader) {}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "src.go", src, 0)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if !HasCgoReference(file, "CHeader") {
+		t.Error("want cgo reference detected for a struct named in a cgo file")
+	}
+	if HasCgoReference(file, "OtherType") {
+		t.Error("want no cgo reference for an unrelated type name")
+	}
+}
+
+func TestComputeBlocksOnFreezeOrder(t *testing.T) {
+	st := types.NewStruct([]*types.Var{
+		newField("ID", types.Typ[types.Uint64]),
+		newField("Active", types.Typ[types.Bool]),
+	}, nil)
+	sizes := types.SizesFor("gc", "amd64")
+
+	plan := Compute(st, sizes, false, false, false, true, false, "")
+	if !plan.Skip {
+		t.Fatal("want Skip=true for a frozen-order struct")
+	}
+	if plan.Optimal != st {
+		t.Error("want Optimal to be the unmodified struct when skipped")
+	}
+	if len(plan.Constraints) != 1 || plan.Constraints[0].Kind != "frozen-order" {
+		t.Errorf("unexpected constraints: %+v", plan.Constraints)
+	}
+}
+
+func TestComputeReordersWithoutConstraints(t *testing.T) {
+	st := types.NewStruct([]*types.Var{
+		newField("Active", types.Typ[types.Bool]),
+		newField("ID", types.Typ[types.Uint64]),
+	}, nil)
+	sizes := types.SizesFor("gc", "amd64")
+
+	plan := Compute(st, sizes, false, false, false, false, false, "")
+	if plan.Skip {
+		t.Fatal("want Skip=false with no constraints")
+	}
+	if got, want := fieldNames(plan.Optimal), []string{"ID", "Active"}; got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("Optimal fields = %v, want %v", got, want)
+	}
+}
+
+func TestComputeKeepsAtomicFieldFirst(t *testing.T) {
+	atomicPkg := types.NewPackage("sync/atomic", "atomic")
+	int64Type := types.NewNamed(types.NewTypeName(token.NoPos, atomicPkg, "Int64", nil), types.NewStruct(nil, nil), nil)
+
+	st := types.NewStruct([]*types.Var{
+		newField("Counter", int64Type),
+		newField("Name", types.NewSlice(types.Typ[types.Byte])),
+		newField("Active", types.Typ[types.Bool]),
+	}, nil)
+	sizes := types.SizesFor("gc", "amd64")
+
+	plan := Compute(st, sizes, false, false, false, false, false, "")
+	if plan.Skip {
+		t.Fatal("an atomic-first constraint should still allow reordering the rest")
+	}
+	if got := fieldNames(plan.Optimal); got[0] != "Counter" {
+		t.Errorf("first field = %s, want Counter pinned at offset 0", got[0])
+	}
+	foundConstraint := false
+	for _, c := range plan.Constraints {
+		if c.Kind == "atomic-first" {
+			foundConstraint = true
+		}
+	}
+	if !foundConstraint {
+		t.Error("want an atomic-first constraint reported even without a pragma")
+	}
+}
@@ -0,0 +1,158 @@
+package scan
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FormatText renders findings as aligncheck-compatible plain text:
+// "pkg/file.go:line: struct Foo could be N bytes (currently M)".
+func FormatText(findings []Finding) string {
+	var b strings.Builder
+	for _, f := range findings {
+		fmt.Fprintf(&b, "%s:%d: struct %s could be %d bytes (currently %d), ~%d estimated instances, ~%d bytes saved overall\n",
+			f.File, f.Line, f.StructName, f.OptimalSize, f.CurrentSize, f.EstimatedInstances, f.EstimatedTotalSavings())
+	}
+	return b.String()
+}
+
+type jsonFinding struct {
+	Package            string `json:"package"`
+	File               string `json:"file"`
+	Line               int    `json:"line"`
+	Struct             string `json:"struct"`
+	CurrentSize        int64  `json:"currentSize"`
+	OptimalSize        int64  `json:"optimalSize"`
+	SavingsPerInstance int64  `json:"savingsPerInstance"`
+	EstimatedInstances int64  `json:"estimatedInstances"`
+	EstimatedTotal     int64  `json:"estimatedTotalSavings"`
+}
+
+// FormatJSON renders findings as a JSON array.
+func FormatJSON(findings []Finding) ([]byte, error) {
+	out := make([]jsonFinding, len(findings))
+	for i, f := range findings {
+		out[i] = jsonFinding{
+			Package:            f.Package,
+			File:               f.File,
+			Line:               f.Line,
+			Struct:             f.StructName,
+			CurrentSize:        f.CurrentSize,
+			OptimalSize:        f.OptimalSize,
+			SavingsPerInstance: f.SavingsPerInstance(),
+			EstimatedInstances: f.EstimatedInstances,
+			EstimatedTotal:     f.EstimatedTotalSavings(),
+		}
+	}
+	return json.MarshalIndent(out, "", "  ")
+}
+
+// sarifReport is the minimal subset of the SARIF 2.1.0 schema GitHub code
+// scanning needs.
+type sarifReport struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string `json:"name"`
+	Rules []struct {
+		ID string `json:"id"`
+	} `json:"rules"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+const sarifRuleID = "gomv/struct-padding"
+
+// sarifURI turns an absolute finding path into the repo-relative,
+// forward-slashed URI GitHub code scanning needs to map a result back to a
+// file in the checkout. If it can't be made relative to root, the original
+// path is used as-is rather than failing the whole report.
+func sarifURI(root, file string) string {
+	if root == "" {
+		return filepath.ToSlash(file)
+	}
+	rel, err := filepath.Rel(root, file)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(file)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// FormatSARIF renders findings as a SARIF 2.1.0 log suitable for GitHub
+// code scanning.
+func FormatSARIF(findings []Finding) ([]byte, error) {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{Name: "gomv"}},
+	}
+	run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, struct {
+		ID string `json:"id"`
+	}{ID: sarifRuleID})
+
+	root, err := os.Getwd()
+	if err != nil {
+		root = ""
+	}
+
+	for _, f := range findings {
+		run.Results = append(run.Results, sarifResult{
+			RuleID: sarifRuleID,
+			Message: sarifMessage{
+				Text: fmt.Sprintf("struct %s could be %d bytes (currently %d), saving ~%d bytes overall",
+					f.StructName, f.OptimalSize, f.CurrentSize, f.EstimatedTotalSavings()),
+			},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: sarifURI(root, f.File)},
+					Region:           sarifRegion{StartLine: f.Line},
+				},
+			}},
+		})
+	}
+
+	report := sarifReport{
+		Schema:  "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}
+	return json.MarshalIndent(report, "", "  ")
+}
@@ -0,0 +1,130 @@
+// Package scan implements the analysis behind `gomv scan ./...`: for every
+// struct in a module, compute the size the compiler currently produces vs.
+// the size a descending-alignment reorder would produce, estimate how many
+// times it's allocated, and rank findings by total estimated bytes saved
+// rather than by per-instance savings.
+package scan
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+)
+
+// Finding is one struct's scan result, ready to be ranked and formatted.
+type Finding struct {
+	Package            string
+	File               string
+	Line               int
+	StructName         string
+	CurrentSize        int64
+	OptimalSize        int64
+	EstimatedInstances int64
+}
+
+// SavingsPerInstance is how many bytes a reorder would save on one instance.
+func (f Finding) SavingsPerInstance() int64 {
+	return f.CurrentSize - f.OptimalSize
+}
+
+// EstimatedTotalSavings is the ranking key: per-instance savings times the
+// (heuristic) number of times the struct is allocated, so a small win in a
+// hot loop can outrank a large win on a singleton.
+func (f Finding) EstimatedTotalSavings() int64 {
+	instances := f.EstimatedInstances
+	if instances < 1 {
+		instances = 1
+	}
+	return f.SavingsPerInstance() * instances
+}
+
+// OptimalOrder returns a new struct type with st's fields sorted by
+// descending alignment (ties broken by descending size, then original
+// declaration order), which is the layout the gc compiler would produce
+// with the smallest padding for this field set.
+func OptimalOrder(sizes types.Sizes, st *types.Struct) *types.Struct {
+	n := st.NumFields()
+	vars := make([]*types.Var, n)
+	for i := 0; i < n; i++ {
+		vars[i] = st.Field(i)
+	}
+
+	sort.SliceStable(vars, func(i, j int) bool {
+		ai, aj := sizes.Alignof(vars[i].Type()), sizes.Alignof(vars[j].Type())
+		if ai != aj {
+			return ai > aj
+		}
+		return sizes.Sizeof(vars[i].Type()) > sizes.Sizeof(vars[j].Type())
+	})
+
+	return types.NewStruct(vars, nil)
+}
+
+// Rank filters findings to those meeting minSavings (per instance) and
+// minInstances, then sorts by EstimatedTotalSavings descending.
+func Rank(findings []Finding, minSavings, minInstances int64) []Finding {
+	kept := make([]Finding, 0, len(findings))
+	for _, f := range findings {
+		if f.SavingsPerInstance() < minSavings {
+			continue
+		}
+		if f.EstimatedInstances < minInstances {
+			continue
+		}
+		kept = append(kept, f)
+	}
+	sort.SliceStable(kept, func(i, j int) bool {
+		return kept[i].EstimatedTotalSavings() > kept[j].EstimatedTotalSavings()
+	})
+	return kept
+}
+
+// EstimateInstances counts allocation-site patterns for structName in file:
+// &structName{...}, new(structName), make([]structName, ...), and
+// map[K]structName. It's a lightweight syntactic heuristic, not a real
+// escape analysis - good enough to rank hot-loop structs above singletons.
+func EstimateInstances(file *ast.File, structName string) int64 {
+	var count int64
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch expr := n.(type) {
+		case *ast.UnaryExpr:
+			if expr.Op == token.AND {
+				if lit, ok := expr.X.(*ast.CompositeLit); ok && identName(lit.Type) == structName {
+					count++
+				}
+			}
+		case *ast.CallExpr:
+			if ident, ok := expr.Fun.(*ast.Ident); ok {
+				switch ident.Name {
+				case "new":
+					if len(expr.Args) == 1 && identName(expr.Args[0]) == structName {
+						count++
+					}
+				case "make":
+					if len(expr.Args) >= 1 {
+						if arr, ok := expr.Args[0].(*ast.ArrayType); ok && identName(arr.Elt) == structName {
+							count++
+						}
+						if m, ok := expr.Args[0].(*ast.MapType); ok && identName(m.Value) == structName {
+							count++
+						}
+					}
+				}
+			}
+		case *ast.MapType:
+			if identName(expr.Value) == structName {
+				count++
+			}
+		}
+		return true
+	})
+	return count
+}
+
+func identName(expr ast.Expr) string {
+	if id, ok := expr.(*ast.Ident); ok {
+		return id.Name
+	}
+	return ""
+}
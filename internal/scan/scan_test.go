@@ -0,0 +1,164 @@
+package scan
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+
+	"github.com/Shomosyt1/go-memory-visualizer/internal/layout"
+)
+
+func newField(name string, typ types.Type) *types.Var {
+	return types.NewField(0, nil, name, typ, false)
+}
+
+func TestOptimalOrderMatchesGoodStruct(t *testing.T) {
+	// Mirrors examples/structs BadStruct/GoodStruct.
+	bad := types.NewStruct([]*types.Var{
+		newField("A", types.Typ[types.Bool]),
+		newField("B", types.Typ[types.Uint64]),
+		newField("C", types.Typ[types.Bool]),
+		newField("D", types.Typ[types.Uint64]),
+		newField("E", types.Typ[types.Uint16]),
+		newField("F", types.Typ[types.Uint64]),
+	}, nil)
+
+	sizes := types.SizesFor("gc", "amd64")
+	optimal := OptimalOrder(sizes, bad)
+
+	if got, want := layout.SizeOf(sizes, bad), int64(48); got != want {
+		t.Fatalf("SizeOf(bad) = %d, want %d", got, want)
+	}
+	if got, want := layout.SizeOf(sizes, optimal), int64(32); got != want {
+		t.Fatalf("SizeOf(optimal) = %d, want %d", got, want)
+	}
+
+	wantOrder := []string{"B", "D", "F", "E", "A", "C"}
+	for i, name := range wantOrder {
+		if optimal.Field(i).Name() != name {
+			t.Errorf("field %d = %s, want %s", i, optimal.Field(i).Name(), name)
+		}
+	}
+}
+
+func TestFindingSavings(t *testing.T) {
+	f := Finding{CurrentSize: 48, OptimalSize: 40, EstimatedInstances: 1000}
+	if f.SavingsPerInstance() != 8 {
+		t.Errorf("SavingsPerInstance = %d, want 8", f.SavingsPerInstance())
+	}
+	if f.EstimatedTotalSavings() != 8000 {
+		t.Errorf("EstimatedTotalSavings = %d, want 8000", f.EstimatedTotalSavings())
+	}
+}
+
+func TestRankHotLoopOutranksSingleton(t *testing.T) {
+	hotLoop := Finding{StructName: "RequestRecord", CurrentSize: 32, OptimalSize: 24, EstimatedInstances: 1000}
+	singleton := Finding{StructName: "ServerConfig", CurrentSize: 32, OptimalSize: 24, EstimatedInstances: 1}
+
+	ranked := Rank([]Finding{singleton, hotLoop}, 0, 0)
+	if len(ranked) != 2 || ranked[0].StructName != "RequestRecord" {
+		t.Fatalf("want RequestRecord ranked first, got %+v", ranked)
+	}
+}
+
+func TestRankFiltersByMinSavingsAndMinInstances(t *testing.T) {
+	findings := []Finding{
+		{StructName: "TinySavings", CurrentSize: 10, OptimalSize: 9, EstimatedInstances: 1000},
+		{StructName: "RareButBig", CurrentSize: 100, OptimalSize: 50, EstimatedInstances: 1},
+	}
+	if got := Rank(findings, 10, 0); len(got) != 1 || got[0].StructName != "RareButBig" {
+		t.Fatalf("min-savings=10 should drop TinySavings, got %+v", got)
+	}
+	if got := Rank(findings, 0, 10); len(got) != 1 || got[0].StructName != "TinySavings" {
+		t.Fatalf("min-instances=10 should drop RareButBig, got %+v", got)
+	}
+}
+
+func parseSrc(t *testing.T, src string) *ast.File {
+	t.Helper()
+	f, err := parser.ParseFile(token.NewFileSet(), "src.go", src, 0)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	return f
+}
+
+func TestEstimateInstances(t *testing.T) {
+	src := `package p
+
+type RequestRecord struct{ ID uint64 }
+
+func handle() {
+	r := &RequestRecord{}
+	_ = r
+	n := new(RequestRecord)
+	_ = n
+	s := make([]RequestRecord, 10)
+	_ = s
+	var m map[string]RequestRecord
+	_ = m
+}
+`
+	file := parseSrc(t, src)
+	if got := EstimateInstances(file, "RequestRecord"); got != 4 {
+		t.Errorf("EstimateInstances = %d, want 4 (&T{}, new(T), make([]T,...), map[K]T)", got)
+	}
+}
+
+func TestEstimateInstancesZeroForUnreferencedType(t *testing.T) {
+	file := parseSrc(t, "package p\n\ntype ServerConfig struct{ Port uint16 }\n")
+	if got := EstimateInstances(file, "ServerConfig"); got != 0 {
+		t.Errorf("EstimateInstances = %d, want 0", got)
+	}
+}
+
+func TestFormatTextIncludesFileLineAndSavings(t *testing.T) {
+	findings := []Finding{
+		{Package: "p", File: "p/f.go", Line: 10, StructName: "Foo", CurrentSize: 32, OptimalSize: 24, EstimatedInstances: 2},
+	}
+	text := FormatText(findings)
+	if !strings.Contains(text, "p/f.go:10: struct Foo could be 24 bytes (currently 32)") {
+		t.Errorf("unexpected text output: %s", text)
+	}
+}
+
+func TestFormatJSONRoundTrips(t *testing.T) {
+	findings := []Finding{{Package: "p", File: "p/f.go", Line: 10, StructName: "Foo", CurrentSize: 32, OptimalSize: 24, EstimatedInstances: 2}}
+	out, err := FormatJSON(findings)
+	if err != nil {
+		t.Fatalf("FormatJSON: %v", err)
+	}
+	if !strings.Contains(string(out), `"struct": "Foo"`) {
+		t.Errorf("missing struct name in JSON: %s", out)
+	}
+	if !strings.Contains(string(out), `"savingsPerInstance": 8`) {
+		t.Errorf("missing computed savings in JSON: %s", out)
+	}
+}
+
+func TestFormatSARIFIncludesRuleAndLocation(t *testing.T) {
+	findings := []Finding{{File: "p/f.go", Line: 10, StructName: "Foo", CurrentSize: 32, OptimalSize: 24, EstimatedInstances: 2}}
+	out, err := FormatSARIF(findings)
+	if err != nil {
+		t.Fatalf("FormatSARIF: %v", err)
+	}
+	s := string(out)
+	if !strings.Contains(s, `"ruleId": "gomv/struct-padding"`) {
+		t.Errorf("missing ruleId: %s", s)
+	}
+	if !strings.Contains(s, `"uri": "p/f.go"`) {
+		t.Errorf("missing artifact location: %s", s)
+	}
+}
+
+func TestSarifURIIsRepoRelative(t *testing.T) {
+	if got := sarifURI("/repo", "/repo/pkg/f.go"); got != "pkg/f.go" {
+		t.Errorf("sarifURI(%q, %q) = %q, want %q", "/repo", "/repo/pkg/f.go", got, "pkg/f.go")
+	}
+	if got := sarifURI("/repo", "/elsewhere/f.go"); got != "/elsewhere/f.go" {
+		t.Errorf("sarifURI outside root should fall back to the original path, got %q", got)
+	}
+}
@@ -0,0 +1,53 @@
+// Package sizeclass mirrors the Go runtime's mspan size classes
+// (runtime/sizeclasses.go) so callers can report the allocation size an
+// object is actually charged, not just its unsafe.Sizeof.
+package sizeclass
+
+// classes is runtime._class_to_size for the "gc" allocator: index 0 is the
+// no-size (large object) sentinel, and index N is the smallest size class
+// that holds N bytes. Values above the last entry are allocated directly in
+// whole pages (PageSize = 8192).
+var classes = []int64{
+	0, 8, 16, 24, 32, 48, 64, 80, 96, 112,
+	128, 144, 160, 176, 192, 208, 224, 240, 256, 288,
+	320, 352, 384, 416, 448, 480, 512, 576, 640, 704,
+	768, 896, 1024, 1152, 1280, 1408, 1536, 1792, 2048, 2304,
+	2688, 3072, 3200, 3456, 4096, 4864, 5376, 6144, 6528, 6784,
+	6912, 8192, 9472, 9728, 10240, 10880, 12288, 13568, 14336, 16384,
+	18432, 19072, 20480, 21760, 24576, 27264, 28672, 32768,
+}
+
+// PageSize is the runtime's allocation granularity for objects larger than
+// the biggest size class.
+const PageSize = 8192
+
+// RoundUp returns the size class index and byte size the runtime would
+// actually charge for an object of size bytes. class is -1 for objects
+// larger than the largest size class, which are rounded up to a whole
+// number of pages instead.
+func RoundUp(size int64) (class int, bytes int64) {
+	if size <= 0 {
+		return 0, 0
+	}
+	for i, c := range classes {
+		if c >= size {
+			return i, c
+		}
+	}
+	pages := (size + PageSize - 1) / PageSize
+	return -1, pages * PageSize
+}
+
+// NextClassDown returns the size class immediately below the one size
+// currently rounds up to, along with whether removing bytes down to that
+// class's size would actually free anything. ok is false when size is
+// already in the smallest class or falls in the unbounded large-object
+// range.
+func NextClassDown(size int64) (class int, bytes int64, downClass int, downBytes int64, ok bool) {
+	class, bytes = RoundUp(size)
+	if class <= 1 {
+		// class 0 is the "no size" sentinel and class 1 has nothing below it.
+		return class, bytes, 0, 0, false
+	}
+	return class, bytes, class - 1, classes[class-1], true
+}
@@ -0,0 +1,53 @@
+package sizeclass
+
+import "testing"
+
+func TestRoundUp(t *testing.T) {
+	cases := []struct {
+		size      int64
+		wantBytes int64
+	}{
+		{1, 8},
+		{8, 8},
+		{9, 16},
+		{9304, 9472},   // examples/sizeclasses TrimmedPastBoundary
+		{10072, 10240}, // examples/sizeclasses NearClassBoundary
+	}
+	for _, c := range cases {
+		if _, bytes := RoundUp(c.size); bytes != c.wantBytes {
+			t.Errorf("RoundUp(%d) bytes = %d, want %d", c.size, bytes, c.wantBytes)
+		}
+	}
+}
+
+func TestRoundUpLargeObjectRoundsToPage(t *testing.T) {
+	class, bytes := RoundUp(40000)
+	if class != -1 {
+		t.Fatalf("class = %d, want -1 (large object)", class)
+	}
+	if bytes%PageSize != 0 || bytes < 40000 {
+		t.Fatalf("bytes = %d, want a page-aligned value >= 40000", bytes)
+	}
+}
+
+func TestNextClassDownMatchesBoundaryExample(t *testing.T) {
+	class, bytes, downClass, downBytes, ok := NextClassDown(10072)
+	if !ok {
+		t.Fatal("NextClassDown(10072) ok = false, want true")
+	}
+	if bytes != 10240 {
+		t.Errorf("current class bytes = %d, want 10240", bytes)
+	}
+	if downBytes != 9728 {
+		t.Errorf("down class bytes = %d, want 9728", downBytes)
+	}
+	if class-downClass != 1 {
+		t.Errorf("downClass should be exactly one class below class, got %d and %d", class, downClass)
+	}
+}
+
+func TestNextClassDownSmallestClassNotOK(t *testing.T) {
+	if _, _, _, _, ok := NextClassDown(1); ok {
+		t.Error("smallest size class should report ok=false, nothing to drop to")
+	}
+}
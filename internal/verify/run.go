@@ -0,0 +1,35 @@
+package verify
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// Run writes source into a throwaway module under a temp directory, runs
+// `go test -bench=. -benchmem` against it, and returns the parsed results.
+// The temp directory is removed before Run returns.
+func Run(source string) ([]Result, error) {
+	dir, err := os.MkdirTemp("", "gomv-verify-")
+	if err != nil {
+		return nil, fmt.Errorf("creating temp module: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module gomvbench\n\ngo 1.21\n"), 0o644); err != nil {
+		return nil, fmt.Errorf("writing go.mod: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "bench_test.go"), []byte(source), 0o644); err != nil {
+		return nil, fmt.Errorf("writing bench_test.go: %w", err)
+	}
+
+	cmd := exec.Command("go", "test", "-bench=.", "-benchmem", "-run=^$")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("go test -bench: %w\n%s", err, out)
+	}
+
+	return ParseBenchOutput(string(out))
+}
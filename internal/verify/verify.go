@@ -0,0 +1,196 @@
+// Package verify turns a struct layout comparison into a measured number:
+// it generates a throwaway Go module containing shape-equivalent structs
+// for the "before" and "after" layouts, runs `go test -bench` against it,
+// and parses the result - so "saves 16 bytes" is backed by a real
+// testing.B run instead of asserted in a comment.
+package verify
+
+import (
+	"fmt"
+	"go/types"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Variant is one struct layout to benchmark: a name ("Before", "After")
+// and the field order to lay it out in.
+type Variant struct {
+	Name   string
+	Struct *types.Struct
+
+	// HotA and HotB, when both set, name the two fields the false-sharing
+	// pass identified as sharing a cache line. GenerateSource emits an
+	// extra contention benchmark for the variant that hammers exactly
+	// those two fields from separate goroutines, so the false-sharing
+	// cost - not just the allocation savings - shows up as a measured
+	// ns/op instead of a comment.
+	HotA, HotB string
+}
+
+// fieldShape returns a Go type expression that reproduces a field's size
+// and alignment without needing the field's real type - which keeps the
+// generated module free of imports the original package might need
+// (sync/atomic, etc.) that a temp module has no reason to depend on.
+func fieldShape(size, align int64) (string, error) {
+	var base string
+	var baseSize int64
+	switch align {
+	case 1:
+		base, baseSize = "uint8", 1
+	case 2:
+		base, baseSize = "uint16", 2
+	case 4:
+		base, baseSize = "uint32", 4
+	case 8:
+		base, baseSize = "uint64", 8
+	default:
+		return "", fmt.Errorf("unsupported alignment %d", align)
+	}
+	if size == 0 {
+		return "struct{}", nil
+	}
+	if size == baseSize {
+		return base, nil
+	}
+	if size%baseSize != 0 {
+		return "", fmt.Errorf("size %d is not a multiple of alignment-sized %s", size, base)
+	}
+	return fmt.Sprintf("[%d]%s", size/baseSize, base), nil
+}
+
+// writeTarget returns the expression that stores through a field of the
+// given generated shape without needing to know the field's real type: an
+// array shape (from a multi-word field) is indexed into its first element,
+// a scalar shape is assigned directly, and a zero-sized shape can't be
+// written to at all.
+func writeTarget(fieldName, shape string) string {
+	switch {
+	case shape == "struct{}":
+		return ""
+	case strings.HasPrefix(shape, "["):
+		return fieldName + "[0]"
+	default:
+		return fieldName
+	}
+}
+
+// GenerateSource renders a single Go file, package bench, containing one
+// struct type, one allocation benchmark, and (for variants with a
+// HotA/HotB pair) one goroutine contention benchmark per variant. The
+// allocation benchmark stores each allocation through a package-level
+// pointer so the compiler can't prove it dead and elide it - a benchmark
+// that lets escape analysis stack-allocate the struct would silently
+// measure nothing. "runtime" and "sync" are only imported when at least one
+// variant actually gets a contention benchmark, so the common case (no
+// false-sharing hot pair) doesn't fail to compile on unused imports.
+func GenerateSource(sizes types.Sizes, variants []Variant) (string, error) {
+	var b strings.Builder
+	var needsContentionImports bool
+
+	for _, v := range variants {
+		writeTargets := map[string]string{}
+		fmt.Fprintf(&b, "type %s struct {\n", v.Name)
+		for i := 0; i < v.Struct.NumFields(); i++ {
+			f := v.Struct.Field(i)
+			shape, err := fieldShape(sizes.Sizeof(f.Type()), int64(sizes.Alignof(f.Type())))
+			if err != nil {
+				return "", fmt.Errorf("field %s: %w", f.Name(), err)
+			}
+			fmt.Fprintf(&b, "\t%s %s\n", f.Name(), shape)
+			writeTargets[f.Name()] = writeTarget(f.Name(), shape)
+		}
+		b.WriteString("}\n\n")
+
+		fmt.Fprintf(&b, "var sink%s *%s\n\n", v.Name, v.Name)
+		fmt.Fprintf(&b, "func Benchmark%s(b *testing.B) {\n", v.Name)
+		b.WriteString("\tb.ReportAllocs()\n")
+		b.WriteString("\tfor i := 0; i < b.N; i++ {\n")
+		fmt.Fprintf(&b, "\t\tsink%s = &%s{}\n", v.Name, v.Name)
+		b.WriteString("\t}\n}\n\n")
+
+		targetA, targetB := writeTargets[v.HotA], writeTargets[v.HotB]
+		if v.HotA == "" || v.HotB == "" || targetA == "" || targetB == "" {
+			continue
+		}
+		needsContentionImports = true
+		fmt.Fprintf(&b, "func Benchmark%sContention(b *testing.B) {\n", v.Name)
+		fmt.Fprintf(&b, "\tv := &%s{}\n", v.Name)
+		b.WriteString("\tprocs := runtime.GOMAXPROCS(0)\n")
+		b.WriteString("\tif procs < 2 {\n\t\tprocs = 2\n\t}\n")
+		b.WriteString("\teach := b.N/procs + 1\n")
+		b.WriteString("\tvar wg sync.WaitGroup\n")
+		b.WriteString("\tb.ResetTimer()\n")
+		b.WriteString("\tfor p := 0; p < procs; p++ {\n")
+		b.WriteString("\t\twg.Add(1)\n")
+		b.WriteString("\t\tgo func(id int) {\n")
+		b.WriteString("\t\t\tdefer wg.Done()\n")
+		b.WriteString("\t\t\tfor i := 0; i < each; i++ {\n")
+		b.WriteString("\t\t\t\tif id%2 == 0 {\n")
+		fmt.Fprintf(&b, "\t\t\t\t\tv.%s++\n", targetA)
+		b.WriteString("\t\t\t\t} else {\n")
+		fmt.Fprintf(&b, "\t\t\t\t\tv.%s++\n", targetB)
+		b.WriteString("\t\t\t\t}\n")
+		b.WriteString("\t\t\t}\n")
+		b.WriteString("\t\t}(p)\n")
+		b.WriteString("\t}\n")
+		b.WriteString("\twg.Wait()\n")
+		b.WriteString("}\n\n")
+	}
+
+	var header strings.Builder
+	if needsContentionImports {
+		header.WriteString("package bench\n\nimport (\n\t\"runtime\"\n\t\"sync\"\n\t\"testing\"\n)\n\n")
+	} else {
+		header.WriteString("package bench\n\nimport \"testing\"\n\n")
+	}
+	return header.String() + b.String(), nil
+}
+
+// Result is one parsed `go test -bench -benchmem` line.
+type Result struct {
+	Name        string
+	NsPerOp     float64
+	BytesPerOp  int64
+	AllocsPerOp int64
+}
+
+// benchLine matches the standard `go test -bench -benchmem` output, e.g.:
+//
+//	BenchmarkBefore-8   	20000000	        55.3 ns/op	      48 B/op	       1 allocs/op
+var benchLine = regexp.MustCompile(`^(Benchmark\S+?)(?:-\d+)?\s+\d+\s+([\d.]+)\s+ns/op\s+(\d+)\s+B/op\s+(\d+)\s+allocs/op`)
+
+// ParseBenchOutput extracts one Result per benchmark line in output,
+// ignoring build logs, PASS/ok summary lines, and anything else `go test`
+// prints alongside the benchmark table.
+func ParseBenchOutput(output string) ([]Result, error) {
+	var results []Result
+	for _, line := range strings.Split(output, "\n") {
+		m := benchLine.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		ns, err := strconv.ParseFloat(m[2], 64)
+		if err != nil {
+			return nil, fmt.Errorf("parsing ns/op in %q: %w", line, err)
+		}
+		bytesPerOp, err := strconv.ParseInt(m[3], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parsing B/op in %q: %w", line, err)
+		}
+		allocs, err := strconv.ParseInt(m[4], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parsing allocs/op in %q: %w", line, err)
+		}
+		results = append(results, Result{
+			Name:        strings.TrimPrefix(m[1], "Benchmark"),
+			NsPerOp:     ns,
+			BytesPerOp:  bytesPerOp,
+			AllocsPerOp: allocs,
+		})
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no benchmark results found in output")
+	}
+	return results, nil
+}
@@ -0,0 +1,127 @@
+package verify
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+)
+
+func newField(name string, typ types.Type) *types.Var {
+	return types.NewField(0, nil, name, typ, false)
+}
+
+// mustTypeCheck parses and type-checks src as a standalone package, failing
+// the test on any error - including "imported and not used", which
+// go/format.Source's syntax-only check can't catch and which is exactly the
+// class of bug GenerateSource needs to avoid (an import pulled in only for
+// one code path, emitted unconditionally).
+func mustTypeCheck(t *testing.T, src string) {
+	t.Helper()
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "bench_test.go", src, 0)
+	if err != nil {
+		t.Fatalf("generated source does not parse: %v\n%s", err, src)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if _, err := conf.Check("bench", fset, []*ast.File{file}, nil); err != nil {
+		t.Fatalf("generated source does not type-check: %v\n%s", err, src)
+	}
+}
+
+func TestGenerateSourceIsValidGo(t *testing.T) {
+	sizes := types.SizesFor("gc", "amd64")
+	before := types.NewStruct([]*types.Var{
+		newField("Active", types.Typ[types.Bool]),
+		newField("ID", types.Typ[types.Uint64]),
+	}, nil)
+	after := types.NewStruct([]*types.Var{
+		newField("ID", types.Typ[types.Uint64]),
+		newField("Active", types.Typ[types.Bool]),
+	}, nil)
+
+	src, err := GenerateSource(sizes, []Variant{{Name: "Before", Struct: before}, {Name: "After", Struct: after}})
+	if err != nil {
+		t.Fatalf("GenerateSource: %v", err)
+	}
+	mustTypeCheck(t, src)
+	if !strings.Contains(src, "type Before struct") || !strings.Contains(src, "type After struct") {
+		t.Errorf("expected both variant types in generated source:\n%s", src)
+	}
+	if !strings.Contains(src, "func BenchmarkBefore(b *testing.B)") {
+		t.Errorf("expected BenchmarkBefore func in generated source:\n%s", src)
+	}
+	if strings.Contains(src, "Contention") {
+		t.Errorf("no HotA/HotB set, expected no contention benchmark:\n%s", src)
+	}
+}
+
+func TestGenerateSourceEmitsContentionBenchmarkForHotPair(t *testing.T) {
+	sizes := types.SizesFor("gc", "amd64")
+	st := types.NewStruct([]*types.Var{
+		newField("Requests", types.Typ[types.Uint64]),
+		newField("Errors", types.Typ[types.Uint64]),
+	}, nil)
+
+	src, err := GenerateSource(sizes, []Variant{{Name: "Before", Struct: st, HotA: "Requests", HotB: "Errors"}})
+	if err != nil {
+		t.Fatalf("GenerateSource: %v", err)
+	}
+	mustTypeCheck(t, src)
+	if !strings.Contains(src, "func BenchmarkBeforeContention(b *testing.B)") {
+		t.Errorf("expected a contention benchmark for the hot pair:\n%s", src)
+	}
+	if !strings.Contains(src, "v.Requests++") || !strings.Contains(src, "v.Errors++") {
+		t.Errorf("expected the contention benchmark to touch both hot fields:\n%s", src)
+	}
+}
+
+func TestGenerateSourceRejectsUnsupportedAlignment(t *testing.T) {
+	sizes := fakeSizes{align: 3}
+	st := types.NewStruct([]*types.Var{newField("X", types.Typ[types.Uint64])}, nil)
+	if _, err := GenerateSource(sizes, []Variant{{Name: "Weird", Struct: st}}); err == nil {
+		t.Fatal("want an error for an alignment fieldShape doesn't know how to reproduce")
+	}
+}
+
+// fakeSizes overrides Alignof to force fieldShape's unsupported-alignment
+// error path, which a real gc target never triggers.
+type fakeSizes struct {
+	align int64
+}
+
+func (f fakeSizes) Alignof(types.Type) int64       { return f.align }
+func (f fakeSizes) Offsetsof([]*types.Var) []int64 { return nil }
+func (f fakeSizes) Sizeof(types.Type) int64        { return 8 }
+
+func TestParseBenchOutput(t *testing.T) {
+	output := `goos: linux
+goarch: amd64
+BenchmarkBefore-8   	20000000	        55.3 ns/op	      48 B/op	       1 allocs/op
+BenchmarkAfter-8    	30000000	        40.1 ns/op	      32 B/op	       1 allocs/op
+PASS
+ok  	gomvbench	2.345s
+`
+	results, err := ParseBenchOutput(output)
+	if err != nil {
+		t.Fatalf("ParseBenchOutput: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2: %+v", len(results), results)
+	}
+	if results[0].Name != "Before" || results[0].BytesPerOp != 48 || results[0].AllocsPerOp != 1 {
+		t.Errorf("unexpected first result: %+v", results[0])
+	}
+	if results[1].Name != "After" || results[1].BytesPerOp != 32 {
+		t.Errorf("unexpected second result: %+v", results[1])
+	}
+}
+
+func TestParseBenchOutputNoMatches(t *testing.T) {
+	if _, err := ParseBenchOutput("PASS\nok  \tgomvbench\t0.001s\n"); err == nil {
+		t.Fatal("want an error when no benchmark lines are present")
+	}
+}